@@ -23,9 +23,23 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type JobWorkerClient interface {
 	StartJob(ctx context.Context, in *StartJobRequest, opts ...grpc.CallOption) (*JobResponse, error)
-	StopJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*JobResponse, error)
 	QueryJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error)
 	StreamJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (JobWorker_StreamJobClient, error)
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	AttachJob(ctx context.Context, opts ...grpc.CallOption) (JobWorker_AttachJobClient, error)
+	// WatchJob sends the job's current state followed by a response for
+	// every status change, and ends once the job stops.  Only the status
+	// of a response belongs to its event, the other fields hold the job's
+	// state when the response was sent, which may already be later.
+	WatchJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (JobWorker_WatchJobClient, error)
+	// DeleteJob removes a stopped job along with its output.
+	DeleteJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	// PruneJobs deletes the caller's stopped jobs that match the request.
+	PruneJobs(ctx context.Context, in *PruneJobsRequest, opts ...grpc.CallOption) (*PruneJobsResponse, error)
+	// StreamJobMetrics samples the job's resources every interval, and
+	// ends once the job stops.
+	StreamJobMetrics(ctx context.Context, in *JobMetricsRequest, opts ...grpc.CallOption) (JobWorker_StreamJobMetricsClient, error)
 }
 
 type jobWorkerClient struct {
@@ -45,7 +59,7 @@ func (c *jobWorkerClient) StartJob(ctx context.Context, in *StartJobRequest, opt
 	return out, nil
 }
 
-func (c *jobWorkerClient) StopJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+func (c *jobWorkerClient) StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*JobResponse, error) {
 	out := new(JobResponse)
 	err := c.cc.Invoke(ctx, "/jobworker.JobWorker/StopJob", in, out, opts...)
 	if err != nil {
@@ -95,14 +109,150 @@ func (x *jobWorkerStreamJobClient) Recv() (*StreamJobResponse, error) {
 	return m, nil
 }
 
+func (c *jobWorkerClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, "/jobworker.JobWorker/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobWorkerClient) AttachJob(ctx context.Context, opts ...grpc.CallOption) (JobWorker_AttachJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &JobWorker_ServiceDesc.Streams[1], "/jobworker.JobWorker/AttachJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jobWorkerAttachJobClient{stream}
+	return x, nil
+}
+
+type JobWorker_AttachJobClient interface {
+	Send(*AttachJobRequest) error
+	Recv() (*StreamJobResponse, error)
+	grpc.ClientStream
+}
+
+type jobWorkerAttachJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *jobWorkerAttachJobClient) Send(m *AttachJobRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *jobWorkerAttachJobClient) Recv() (*StreamJobResponse, error) {
+	m := new(StreamJobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *jobWorkerClient) WatchJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (JobWorker_WatchJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &JobWorker_ServiceDesc.Streams[2], "/jobworker.JobWorker/WatchJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jobWorkerWatchJobClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type JobWorker_WatchJobClient interface {
+	Recv() (*JobResponse, error)
+	grpc.ClientStream
+}
+
+type jobWorkerWatchJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *jobWorkerWatchJobClient) Recv() (*JobResponse, error) {
+	m := new(JobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *jobWorkerClient) DeleteJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, "/jobworker.JobWorker/DeleteJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobWorkerClient) PruneJobs(ctx context.Context, in *PruneJobsRequest, opts ...grpc.CallOption) (*PruneJobsResponse, error) {
+	out := new(PruneJobsResponse)
+	err := c.cc.Invoke(ctx, "/jobworker.JobWorker/PruneJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobWorkerClient) StreamJobMetrics(ctx context.Context, in *JobMetricsRequest, opts ...grpc.CallOption) (JobWorker_StreamJobMetricsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &JobWorker_ServiceDesc.Streams[3], "/jobworker.JobWorker/StreamJobMetrics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jobWorkerStreamJobMetricsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type JobWorker_StreamJobMetricsClient interface {
+	Recv() (*JobMetrics, error)
+	grpc.ClientStream
+}
+
+type jobWorkerStreamJobMetricsClient struct {
+	grpc.ClientStream
+}
+
+func (x *jobWorkerStreamJobMetricsClient) Recv() (*JobMetrics, error) {
+	m := new(JobMetrics)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // JobWorkerServer is the server API for JobWorker service.
 // All implementations must embed UnimplementedJobWorkerServer
 // for forward compatibility
 type JobWorkerServer interface {
 	StartJob(context.Context, *StartJobRequest) (*JobResponse, error)
-	StopJob(context.Context, *JobRequest) (*JobResponse, error)
+	StopJob(context.Context, *StopJobRequest) (*JobResponse, error)
 	QueryJob(context.Context, *JobRequest) (*JobResponse, error)
 	StreamJob(*JobRequest, JobWorker_StreamJobServer) error
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	AttachJob(JobWorker_AttachJobServer) error
+	// WatchJob sends the job's current state followed by a response for
+	// every status change, and ends once the job stops.  Only the status
+	// of a response belongs to its event, the other fields hold the job's
+	// state when the response was sent, which may already be later.
+	WatchJob(*JobRequest, JobWorker_WatchJobServer) error
+	// DeleteJob removes a stopped job along with its output.
+	DeleteJob(context.Context, *JobRequest) (*JobResponse, error)
+	// PruneJobs deletes the caller's stopped jobs that match the request.
+	PruneJobs(context.Context, *PruneJobsRequest) (*PruneJobsResponse, error)
+	// StreamJobMetrics samples the job's resources every interval, and
+	// ends once the job stops.
+	StreamJobMetrics(*JobMetricsRequest, JobWorker_StreamJobMetricsServer) error
 	mustEmbedUnimplementedJobWorkerServer()
 }
 
@@ -113,7 +263,7 @@ type UnimplementedJobWorkerServer struct {
 func (UnimplementedJobWorkerServer) StartJob(context.Context, *StartJobRequest) (*JobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartJob not implemented")
 }
-func (UnimplementedJobWorkerServer) StopJob(context.Context, *JobRequest) (*JobResponse, error) {
+func (UnimplementedJobWorkerServer) StopJob(context.Context, *StopJobRequest) (*JobResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopJob not implemented")
 }
 func (UnimplementedJobWorkerServer) QueryJob(context.Context, *JobRequest) (*JobResponse, error) {
@@ -122,6 +272,24 @@ func (UnimplementedJobWorkerServer) QueryJob(context.Context, *JobRequest) (*Job
 func (UnimplementedJobWorkerServer) StreamJob(*JobRequest, JobWorker_StreamJobServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamJob not implemented")
 }
+func (UnimplementedJobWorkerServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedJobWorkerServer) AttachJob(JobWorker_AttachJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method AttachJob not implemented")
+}
+func (UnimplementedJobWorkerServer) WatchJob(*JobRequest, JobWorker_WatchJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
+func (UnimplementedJobWorkerServer) DeleteJob(context.Context, *JobRequest) (*JobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteJob not implemented")
+}
+func (UnimplementedJobWorkerServer) PruneJobs(context.Context, *PruneJobsRequest) (*PruneJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneJobs not implemented")
+}
+func (UnimplementedJobWorkerServer) StreamJobMetrics(*JobMetricsRequest, JobWorker_StreamJobMetricsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamJobMetrics not implemented")
+}
 func (UnimplementedJobWorkerServer) mustEmbedUnimplementedJobWorkerServer() {}
 
 // UnsafeJobWorkerServer may be embedded to opt out of forward compatibility for this service.
@@ -154,7 +322,7 @@ func _JobWorker_StartJob_Handler(srv interface{}, ctx context.Context, dec func(
 }
 
 func _JobWorker_StopJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(JobRequest)
+	in := new(StopJobRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -166,7 +334,7 @@ func _JobWorker_StopJob_Handler(srv interface{}, ctx context.Context, dec func(i
 		FullMethod: "/jobworker.JobWorker/StopJob",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(JobWorkerServer).StopJob(ctx, req.(*JobRequest))
+		return srv.(JobWorkerServer).StopJob(ctx, req.(*StopJobRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -210,6 +378,128 @@ func (x *jobWorkerStreamJobServer) Send(m *StreamJobResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _JobWorker_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobWorkerServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobworker.JobWorker/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobWorkerServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobWorker_AttachJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(JobWorkerServer).AttachJob(&jobWorkerAttachJobServer{stream})
+}
+
+type JobWorker_AttachJobServer interface {
+	Send(*StreamJobResponse) error
+	Recv() (*AttachJobRequest, error)
+	grpc.ServerStream
+}
+
+type jobWorkerAttachJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *jobWorkerAttachJobServer) Send(m *StreamJobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *jobWorkerAttachJobServer) Recv() (*AttachJobRequest, error) {
+	m := new(AttachJobRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _JobWorker_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JobWorkerServer).WatchJob(m, &jobWorkerWatchJobServer{stream})
+}
+
+type JobWorker_WatchJobServer interface {
+	Send(*JobResponse) error
+	grpc.ServerStream
+}
+
+type jobWorkerWatchJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *jobWorkerWatchJobServer) Send(m *JobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _JobWorker_DeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobWorkerServer).DeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobworker.JobWorker/DeleteJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobWorkerServer).DeleteJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobWorker_PruneJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobWorkerServer).PruneJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/jobworker.JobWorker/PruneJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobWorkerServer).PruneJobs(ctx, req.(*PruneJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _JobWorker_StreamJobMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(JobWorkerServer).StreamJobMetrics(m, &jobWorkerStreamJobMetricsServer{stream})
+}
+
+type JobWorker_StreamJobMetricsServer interface {
+	Send(*JobMetrics) error
+	grpc.ServerStream
+}
+
+type jobWorkerStreamJobMetricsServer struct {
+	grpc.ServerStream
+}
+
+func (x *jobWorkerStreamJobMetricsServer) Send(m *JobMetrics) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // JobWorker_ServiceDesc is the grpc.ServiceDesc for JobWorker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +519,18 @@ var JobWorker_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryJob",
 			Handler:    _JobWorker_QueryJob_Handler,
 		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _JobWorker_ListJobs_Handler,
+		},
+		{
+			MethodName: "DeleteJob",
+			Handler:    _JobWorker_DeleteJob_Handler,
+		},
+		{
+			MethodName: "PruneJobs",
+			Handler:    _JobWorker_PruneJobs_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -236,6 +538,22 @@ var JobWorker_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _JobWorker_StreamJob_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "AttachJob",
+			Handler:       _JobWorker_AttachJob_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchJob",
+			Handler:       _JobWorker_WatchJob_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamJobMetrics",
+			Handler:       _JobWorker_StreamJobMetrics_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pkg/api/jobworker.proto",
 }
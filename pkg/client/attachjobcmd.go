@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	pb "jobworker/pkg/api"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+const stdinBufferSize = 32 << 10
+
+type AttachJobCommand struct {
+	*commonCommand
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func NewAttachJobCommand() *AttachJobCommand {
+	cmd := &AttachJobCommand{
+		commonCommand: &commonCommand{
+			fs: flag.NewFlagSet("attach", flag.ExitOnError),
+		},
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	cmd.addCommonFlags()
+	return cmd
+}
+
+// Run:
+// - Attaches to the job and sends the local stdin to it in the background
+// - Switches the local terminal to raw mode if the job has a terminal
+// - Writes the job's stdout and stderr to the local stdout and stderr until
+// the job ends
+func (c *AttachJobCommand) Run(ctx context.Context) ([]byte, error) {
+	log.Printf("Executing attach command with args=%v", c.fs.Args())
+
+	if len(c.fs.Args()) == 0 {
+		return nil, fmt.Errorf("missing argument jobId")
+	}
+
+	jobID := c.fs.Args()[0]
+
+	stream, err := c.client.AttachJob(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error attaching job: %w", err)
+	}
+
+	if err := stream.Send(&pb.AttachJobRequest{JobId: jobID}); err != nil {
+		return nil, fmt.Errorf("error attaching job: %w", err)
+	}
+
+	sender := &attachSender{stream: stream}
+
+	restore, err := c.attachTerminal(ctx, sender, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	go func() {
+		if err := sendStdin(sender, c.stdin); err != nil {
+			log.Printf("Sending stdin failed: %v", err)
+		}
+	}()
+
+	var output []byte
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error while receiving data: %w", err)
+		}
+
+		out := c.stdout
+		if resp.Stream == pb.OutputStream_outputStderr {
+			out = c.stderr
+		}
+		if _, err := out.Write(resp.Message); err != nil {
+			return nil, fmt.Errorf("error writing output: %w", err)
+		}
+
+		output = append(output, resp.Message...)
+	}
+
+	return output, nil
+}
+
+// attachSender serializes the messages sent on the attach stream, as both
+// stdin and terminal resizes are sent from their own goroutines.
+type attachSender struct {
+	mu     sync.Mutex
+	stream pb.JobWorker_AttachJobClient
+}
+
+func (s *attachSender) Send(req *pb.AttachJobRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stream.Send(req)
+}
+
+func (s *attachSender) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stream.CloseSend()
+}
+
+// attachTerminal:
+// - Does nothing unless both the local stdin and the job have a terminal
+// - Puts the local terminal in raw mode and sends its size to the job
+// - Sends the new size whenever the local terminal is resized
+// - Returns a function that restores the local terminal
+func (c *AttachJobCommand) attachTerminal(ctx context.Context, sender *attachSender, jobID string) (func(), error) {
+	file, ok := c.stdin.(*os.File)
+	if !ok || !isTerminal(int(file.Fd())) {
+		return func() {}, nil
+	}
+	fd := int(file.Fd())
+
+	job, err := c.client.QueryJob(ctx, &pb.JobRequest{JobId: jobID})
+	if err != nil {
+		return nil, fmt.Errorf("error querying job: %w", err)
+	}
+	if !job.Tty {
+		return func() {}, nil
+	}
+
+	restoreTerminal, err := makeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	resizeCh := make(chan os.Signal, 1)
+	signal.Notify(resizeCh, unix.SIGWINCH)
+	// Send the initial size as well
+	resizeCh <- unix.SIGWINCH
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-resizeCh:
+				if err := sender.Send(&pb.AttachJobRequest{Resize: windowSize(fd)}); err != nil {
+					log.Printf("Sending terminal size failed: %v", err)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(resizeCh)
+		close(done)
+		if err := restoreTerminal(); err != nil {
+			log.Printf("Restoring terminal failed: %v", err)
+		}
+	}, nil
+}
+
+// sendStdin reads the local stdin until EOF, at which point the job's
+// stdin is closed as well.
+func sendStdin(stream *attachSender, stdin io.Reader) error {
+	buffer := make([]byte, stdinBufferSize)
+
+	for {
+		n, err := stdin.Read(buffer)
+		if n > 0 {
+			if err := stream.Send(&pb.AttachJobRequest{Stdin: buffer[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			if err := stream.Send(&pb.AttachJobRequest{CloseStdin: true}); err != nil {
+				return err
+			}
+			return stream.CloseSend()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -2,17 +2,23 @@ package client_test
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	pb "jobworker/pkg/api"
 	"jobworker/pkg/client"
 	"jobworker/pkg/server"
+	"jobworker/pkg/server/servertest"
+	"net"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func getServer(t *testing.T, port string) *server.JobWorkerServer {
@@ -21,6 +27,8 @@ func getServer(t *testing.T, port string) *server.JobWorkerServer {
 	os.Setenv("JOBWORKER_SERVER_TEST", "yes")
 	os.Setenv("JOBWORKER_SERVER_CERT_DIR", "../../certs")
 	os.Setenv("JOBWORKER_SERVER_PORT", port)
+	os.Setenv("JOBWORKER_SERVER_USER_MAP", servertest.WriteUserMap(t))
+	os.Setenv("JOBWORKER_SERVER_LOG_DIR", t.TempDir())
 
 	srv, err := server.NewJobWorkerServer()
 	if err != nil {
@@ -90,7 +98,17 @@ func TestClient(t *testing.T) {
 	args = getArgs("stop", []string{resp.JobId})
 	resp = execCmdForJobResponse(t, args)
 
-	time.Sleep(time.Second)
+	// watch returns once the job stops
+	args = getArgs("watch", []string{resp.JobId})
+	watchOutput, err := client.ExecuteCommand(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Execute command failed %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(watchOutput)), "\n")
+	if !strings.Contains(lines[len(lines)-1], "jobStopped") {
+		t.Fatalf("Expected watch to end with jobStopped, output=%s", watchOutput)
+	}
 
 	args = getArgs("status", []string{resp.JobId})
 	resp = execCmdForJobResponse(t, args)
@@ -107,4 +125,150 @@ func TestClient(t *testing.T) {
 	if !strings.HasPrefix(string(output), prefix) {
 		t.Fatalf("Unexpected output=%s, expected=%s", string(output), prefix)
 	}
+
+	// logs prints the same output, or every line after its timestamp
+	args = getArgs("logs", []string{resp.JobId})
+	logsOutput, err := client.ExecuteCommand(context.Background(), args)
+	if err != nil || string(logsOutput) != string(output) {
+		t.Fatalf("Expected logs to print %q, received %q: %v", output, logsOutput, err)
+	}
+
+	args = getArgs("logs", []string{"-timestamps", resp.JobId})
+	logsOutput, err = client.ExecuteCommand(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Execute command failed %v", err)
+	}
+
+	var texts []string
+	for i, line := range strings.Split(strings.TrimSuffix(string(logsOutput), "\n"), "\n") {
+		timestamp, text, _ := strings.Cut(line, " ")
+		if _, err := time.Parse(time.RFC3339Nano, timestamp); err != nil || text != fmt.Sprint(i+1) {
+			t.Fatalf("Unexpected timestamped line %q: %v", line, err)
+		}
+		texts = append(texts, text)
+	}
+	if strings.Join(texts, "\n")+"\n" != string(output) {
+		t.Fatalf("Expected timestamped logs of %q, received %q", output, logsOutput)
+	}
+
+	args = getArgs("rm", []string{resp.JobId})
+	resp = execCmdForJobResponse(t, args)
+
+	args = getArgs("status", []string{resp.JobId})
+	if _, err := client.ExecuteCommand(context.Background(), args); err == nil {
+		t.Fatalf("Expected status of a deleted job to fail")
+	}
+
+	args = getArgs("start", []string{"true"})
+	resp = execCmdForJobResponse(t, args)
+
+	args = getArgs("watch", []string{resp.JobId})
+	if _, err := client.ExecuteCommand(context.Background(), args); err != nil {
+		t.Fatalf("Execute command failed %v", err)
+	}
+
+	args = getArgs("prune", nil)
+	pruneOutput, err := client.ExecuteCommand(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Execute command failed %v", err)
+	}
+
+	if !strings.Contains(string(pruneOutput), resp.JobId) {
+		t.Fatalf("Expected prune to delete %s, output=%s", resp.JobId, pruneOutput)
+	}
+}
+
+func TestStartFailedCommand(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "6790")
+	defer srv.Close()
+
+	args := []string{
+		"start",
+		"-ca", "../../certs/ca.crt",
+		"-cert", "../../certs/alice.crt",
+		"-key", "../../certs/alice.key",
+		"-server-addr", "localhost:6790",
+		"no-such-command",
+	}
+
+	// The job is still returned, but start fails like the command did
+	output, err := client.ExecuteCommand(context.Background(), args)
+	if err == nil || !strings.Contains(err.Error(), "no-such-command") {
+		t.Fatalf("expected start to fail with the job's error message, received %v", err)
+	}
+
+	var jobResp pb.JobResponse
+	if err := protojson.Unmarshal(output, &jobResp); err != nil {
+		t.Fatalf("Unmarshal failed %v", err)
+	}
+	if jobResp.Status != pb.JobStatus_jobFailedToStart {
+		t.Fatalf("Job should have failed to start, status=%v", jobResp.Status)
+	}
+}
+
+// metricsServer sends a single sample of a job's resources
+type metricsServer struct {
+	pb.UnimplementedJobWorkerServer
+	sample *pb.JobMetrics
+}
+
+func (s *metricsServer) StreamJobMetrics(req *pb.JobMetricsRequest, stream pb.JobWorker_StreamJobMetricsServer) error {
+	return stream.Send(s.sample)
+}
+
+func TestTopCommand(t *testing.T) {
+	t.Parallel()
+
+	cert, err := tls.LoadX509KeyPair("../../certs/server.crt", "../../certs/server.key")
+	if err != nil {
+		t.Fatalf("failed loading server certificate: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed listening: %v", err)
+	}
+
+	sample := &pb.JobMetrics{
+		Time:               timestamppb.Now(),
+		CpuPercent:         12.5,
+		MemoryCurrentBytes: 64 << 20,
+		MemoryPeakBytes:    128 << 20,
+		ReadBytesPerSec:    1 << 10,
+		PidsCurrent:        3,
+		IoPressure:         &pb.Pressure{Some: &pb.PressureStats{Avg10: 1.5}},
+	}
+
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+	pb.RegisterJobWorkerServer(srv, &metricsServer{sample: sample})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	args := []string{
+		"top",
+		"-ca", "../../certs/ca.crt",
+		"-cert", "../../certs/alice.crt",
+		"-key", "../../certs/alice.key",
+		"-server-addr", fmt.Sprintf("localhost:%d", lis.Addr().(*net.TCPAddr).Port),
+		"job",
+	}
+	output, err := client.ExecuteCommand(context.Background(), args)
+	if err != nil {
+		t.Fatalf("Execute command failed %v", err)
+	}
+
+	for _, expected := range []string{
+		"Job job at ",
+		"CPU      12.5%",
+		"Memory   64.0M (peak 128.0M)",
+		"IO       read 1.0K/s (0 iops), write 0B/s (0 iops)",
+		"Pids     3",
+		"io          1.50%    0.00%    0.00%    0.00%",
+	} {
+		if !strings.Contains(string(output), expected) {
+			t.Fatalf("expected top to print %q, output=%s", expected, output)
+		}
+	}
 }
@@ -99,7 +99,10 @@ func (c *commonCommand) initGrpcClient() error {
 //	Some examples to execute the commands are:
 //
 // ./jobclient start -- ls -l /dev/null
+// ./jobclient start -io-limit /data,wbps=10M,wiops=100 -- dd if=/dev/zero of=/data/zero bs=1M count=100
 // ./jobclient stream $jobID
+// ./jobclient logs --timestamps $jobID
+// ./jobclient top -interval 2s $jobID
 func ExecuteCommand(ctx context.Context, args []string) ([]byte, error) {
 	if len(args) < 1 {
 		return nil, fmt.Errorf("you must pass a sub-command")
@@ -110,6 +113,13 @@ func ExecuteCommand(ctx context.Context, args []string) ([]byte, error) {
 		NewQueryJobCommand(),
 		NewStopJobCommand(),
 		NewStreamJobCommand(),
+		NewLogsCommand(),
+		NewListJobsCommand(),
+		NewAttachJobCommand(),
+		NewWatchJobCommand(),
+		NewDeleteJobCommand(),
+		NewPruneJobsCommand(),
+		NewTopJobCommand(),
 	}
 
 	subcommand := args[0]
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	pb "jobworker/pkg/api"
+	"log"
+)
+
+type DeleteJobCommand struct {
+	*commonCommand
+}
+
+func NewDeleteJobCommand() *DeleteJobCommand {
+	cmd := &DeleteJobCommand{
+		commonCommand: &commonCommand{
+			fs: flag.NewFlagSet("rm", flag.ExitOnError),
+		},
+	}
+
+	cmd.addCommonFlags()
+	return cmd
+}
+
+func (c *DeleteJobCommand) Run(ctx context.Context) ([]byte, error) {
+	log.Printf("Executing rm command with args=%v", c.fs.Args())
+
+	if len(c.fs.Args()) == 0 {
+		return nil, fmt.Errorf("missing argument jobId")
+	}
+
+	req := pb.JobRequest{
+		JobId: c.fs.Args()[0],
+	}
+
+	resp, err := c.client.DeleteJob(ctx, &req)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting job: %w", err)
+	}
+
+	return marshalPrintJobResponse(resp)
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"fmt"
+	pb "jobworker/pkg/api"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// keyValueFlag is a repeatable flag of the form `-flag key=value`
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f keyValueFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	f[key] = val
+	return nil
+}
+
+// stringsFlag is a repeatable flag that keeps its values in order
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// ioLimitFlag is a repeatable flag that limits a single device, of the form
+// `-flag device,rbps=10M,wbps=5M,riops=100,wiops=50`.  The device comes
+// first since a major:minor holds a ':', and every limit is optional.
+type ioLimitFlag []*pb.IOLimit
+
+func (f *ioLimitFlag) String() string {
+	limits := make([]string, 0, len(*f))
+	for _, limit := range *f {
+		limits = append(limits, limit.GetDevice())
+	}
+	return strings.Join(limits, ",")
+}
+
+func (f *ioLimitFlag) Set(value string) error {
+	fields := strings.Split(value, ",")
+	limit := &pb.IOLimit{Device: strings.TrimSpace(fields[0])}
+	if limit.Device == "" {
+		return fmt.Errorf("expected device,key=value..., got %q", value)
+	}
+
+	for _, field := range fields[1:] {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			return fmt.Errorf("expected key=value, got %q", field)
+		}
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case "rbps":
+			limit.ReadBytesPerSec, err = parseBytes(val)
+		case "wbps":
+			limit.WriteBytesPerSec, err = parseBytes(val)
+		case "riops":
+			limit.ReadIops, err = strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+		case "wiops":
+			limit.WriteIops, err = strconv.ParseInt(strings.TrimSpace(val), 10, 64)
+		default:
+			return fmt.Errorf("unknown io limit %q, expected rbps, wbps, riops or wiops", key)
+		}
+		if err != nil {
+			return fmt.Errorf("bad %s value: %w", key, err)
+		}
+	}
+
+	*f = append(*f, limit)
+	return nil
+}
+
+// readEnvFile reads KEY=VALUE lines from a file, skipping blank lines and
+// lines that start with a '#'.
+func readEnvFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading env file %s: %w", path, err)
+	}
+
+	var env []string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, "="); !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, i+1)
+		}
+		env = append(env, line)
+	}
+
+	return env, nil
+}
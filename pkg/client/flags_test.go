@@ -0,0 +1,38 @@
+package client
+
+import (
+	pb "jobworker/pkg/api"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestIOLimitFlag(t *testing.T) {
+	t.Parallel()
+
+	var limits ioLimitFlag
+	for _, value := range []string{"/data,rbps=10M,wbps=1.5K,riops=100,wiops=50", "8:16,wiops=10"} {
+		if err := limits.Set(value); err != nil {
+			t.Fatalf("Set(%q) failed: %v", value, err)
+		}
+	}
+
+	expected := []*pb.IOLimit{
+		{Device: "/data", ReadBytesPerSec: 10 << 20, WriteBytesPerSec: 1536, ReadIops: 100, WriteIops: 50},
+		{Device: "8:16", WriteIops: 10},
+	}
+	if len(limits) != len(expected) {
+		t.Fatalf("expected %d limits, received %v", len(expected), limits)
+	}
+	for i := range expected {
+		if !proto.Equal(limits[i], expected[i]) {
+			t.Fatalf("expected limit %v, received %v", expected[i], limits[i])
+		}
+	}
+
+	for _, value := range []string{"", ",rbps=1M", "/data,rbps", "/data,xbps=1M", "/data,riops=1K"} {
+		if err := limits.Set(value); err == nil {
+			t.Fatalf("Set(%q) should have failed", value)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	pb "jobworker/pkg/api"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ListJobsCommand struct {
+	*commonCommand
+	statuses      string
+	command       string
+	startedAfter  string
+	startedBefore string
+	since         time.Duration
+	labels        keyValueFlag
+	pageSize      int
+	pageToken     string
+	all           bool
+}
+
+func NewListJobsCommand() *ListJobsCommand {
+	cmd := &ListJobsCommand{
+		commonCommand: &commonCommand{
+			fs: flag.NewFlagSet("list", flag.ExitOnError),
+		},
+		labels: keyValueFlag{},
+	}
+
+	cmd.addCommonFlags()
+	cmd.fs.StringVar(&cmd.statuses, "status", "", "Comma separated statuses to list (e.g running,stopped)")
+	cmd.fs.StringVar(&cmd.command, "command", "", "List only jobs running this command")
+	cmd.fs.StringVar(&cmd.startedAfter, "started-after", "", "List jobs started after this RFC3339 time")
+	cmd.fs.StringVar(&cmd.startedBefore, "started-before", "", "List jobs started before this RFC3339 time")
+	cmd.fs.DurationVar(&cmd.since, "since", 0, "List jobs started within this duration (e.g 1h)")
+	cmd.fs.Var(cmd.labels, "label", "List jobs with the label key=value, may be repeated")
+	cmd.fs.IntVar(&cmd.pageSize, "page-size", 0, "Maximum number of jobs to return")
+	cmd.fs.StringVar(&cmd.pageToken, "page-token", "", "Token of the page to return")
+	cmd.fs.BoolVar(&cmd.all, "all", false, "Fetch all pages")
+
+	return cmd
+}
+
+func (c *ListJobsCommand) Run(ctx context.Context) ([]byte, error) {
+	log.Printf("Executing list command with args=%v", c.fs.Args())
+
+	req, err := c.listJobsRequest()
+	if err != nil {
+		return nil, fmt.Errorf("invalid list flags: %w", err)
+	}
+
+	resp, err := c.client.ListJobs(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("error listing jobs: %w", err)
+	}
+
+	// Keep on fetching pages and collect the jobs into a single response
+	for c.all && resp.NextPageToken != "" {
+		req.PageToken = resp.NextPageToken
+
+		page, err := c.client.ListJobs(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("error listing jobs: %w", err)
+		}
+
+		resp.Jobs = append(resp.Jobs, page.Jobs...)
+		resp.NextPageToken = page.NextPageToken
+	}
+
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal response: %w", err)
+	}
+
+	fmt.Print(string(data))
+
+	return data, nil
+}
+
+// Converts the command flags to a ListJobsRequest
+func (c *ListJobsCommand) listJobsRequest() (*pb.ListJobsRequest, error) {
+	req := &pb.ListJobsRequest{
+		Command:   c.command,
+		Labels:    c.labels,
+		PageSize:  int32(c.pageSize),
+		PageToken: c.pageToken,
+	}
+
+	if c.statuses != "" {
+		for _, name := range strings.Split(c.statuses, ",") {
+			status, err := parseJobStatus(name)
+			if err != nil {
+				return nil, err
+			}
+			req.Statuses = append(req.Statuses, status)
+		}
+	}
+
+	if c.since > 0 {
+		req.StartedAfter = timestamppb.New(time.Now().Add(-c.since))
+	}
+
+	if c.startedAfter != "" {
+		t, err := time.Parse(time.RFC3339, c.startedAfter)
+		if err != nil {
+			return nil, fmt.Errorf("bad --started-after value: %w", err)
+		}
+		req.StartedAfter = timestamppb.New(t)
+	}
+
+	if c.startedBefore != "" {
+		t, err := time.Parse(time.RFC3339, c.startedBefore)
+		if err != nil {
+			return nil, fmt.Errorf("bad --started-before value: %w", err)
+		}
+		req.StartedBefore = timestamppb.New(t)
+	}
+
+	return req, nil
+}
+
+// parseJobStatus accepts both the api names (jobRunning) and the short
+// names (running) of a status, regardless of case.
+func parseJobStatus(name string) (pb.JobStatus, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	for value, statusName := range pb.JobStatus_name {
+		statusName = strings.ToLower(statusName)
+		if name == statusName || "job"+name == statusName {
+			return pb.JobStatus(value), nil
+		}
+	}
+
+	return pb.JobStatus_jobInit, fmt.Errorf("unknown job status %q", name)
+}
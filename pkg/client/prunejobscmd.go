@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	pb "jobworker/pkg/api"
+	"log"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type PruneJobsCommand struct {
+	*commonCommand
+	olderThan time.Duration
+	command   string
+	labels    keyValueFlag
+}
+
+func NewPruneJobsCommand() *PruneJobsCommand {
+	cmd := &PruneJobsCommand{
+		commonCommand: &commonCommand{
+			fs: flag.NewFlagSet("prune", flag.ExitOnError),
+		},
+		labels: keyValueFlag{},
+	}
+
+	cmd.addCommonFlags()
+	cmd.fs.DurationVar(&cmd.olderThan, "older-than", 0, "Delete only jobs that finished at least this long ago (e.g 24h)")
+	cmd.fs.StringVar(&cmd.command, "command", "", "Delete only jobs running this command")
+	cmd.fs.Var(cmd.labels, "label", "Delete only jobs with the label key=value, may be repeated")
+
+	return cmd
+}
+
+// Run:
+// - Deletes all of the stopped jobs that match the flags
+// - Prints the deleted jobs
+func (c *PruneJobsCommand) Run(ctx context.Context) ([]byte, error) {
+	log.Printf("Executing prune command with args=%v", c.fs.Args())
+
+	req := pb.PruneJobsRequest{
+		Command: c.command,
+		Labels:  c.labels,
+	}
+
+	if c.olderThan > 0 {
+		req.OlderThan = durationpb.New(c.olderThan)
+	}
+
+	resp, err := c.client.PruneJobs(ctx, &req)
+	if err != nil {
+		return nil, fmt.Errorf("error pruning jobs: %w", err)
+	}
+
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal response: %w", err)
+	}
+
+	fmt.Print(string(data))
+
+	return data, nil
+}
@@ -7,10 +7,29 @@ import (
 	"fmt"
 	pb "jobworker/pkg/api"
 	"log"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type StartJobCommand struct {
 	*commonCommand
+	cpus       float64
+	memory     string
+	ioBps      string
+	ioLimits   ioLimitFlag
+	labels     keyValueFlag
+	env        stringsFlag
+	envFile    string
+	workDir    string
+	inheritEnv bool
+	openStdin  bool
+	tty        bool
+	timeout    time.Duration
+	logLimit   string
+	logPolicy  string
 }
 
 func NewStartJobCommand() *StartJobCommand {
@@ -18,9 +37,26 @@ func NewStartJobCommand() *StartJobCommand {
 		commonCommand: &commonCommand{
 			fs: flag.NewFlagSet("start", flag.ExitOnError),
 		},
+		labels: keyValueFlag{},
 	}
 
 	cmd.addCommonFlags()
+	cmd.fs.Float64Var(&cmd.cpus, "cpu", 0, "Number of cpus the job may use (e.g 1.5)")
+	cmd.fs.StringVar(&cmd.memory, "memory", "", "Maximum memory for the job (e.g 512M)")
+	cmd.fs.StringVar(&cmd.ioBps, "io-bps", "", "Maximum disk read/write rate per second (e.g 10M)")
+	cmd.fs.Var(&cmd.ioLimits, "io-limit",
+		"Limit a device as device,rbps=10M,wbps=5M,riops=100,wiops=50, may be repeated. "+
+			"The device is a path on its filesystem, its node or its major:minor")
+	cmd.fs.Var(cmd.labels, "label", "Label to attach to the job as key=value, may be repeated")
+	cmd.fs.Var(&cmd.env, "env", "Environment variable for the job as KEY=VALUE, may be repeated")
+	cmd.fs.StringVar(&cmd.envFile, "env-file", "", "File with KEY=VALUE environment variables for the job")
+	cmd.fs.StringVar(&cmd.workDir, "workdir", "", "Working directory of the job")
+	cmd.fs.BoolVar(&cmd.inheritEnv, "inherit-env", false, "Inherit the server's environment instead of a clean one")
+	cmd.fs.BoolVar(&cmd.openStdin, "stdin", false, "Keep stdin open so it can be written with attach")
+	cmd.fs.BoolVar(&cmd.tty, "tty", false, "Run the job with a terminal, sized like the local one")
+	cmd.fs.DurationVar(&cmd.timeout, "timeout", 0, "Stop the job once it runs longer than this, e.g 1h (default set by the server)")
+	cmd.fs.StringVar(&cmd.logLimit, "log-limit", "", "Maximum size of the job's output, e.g 100M (default set by the server)")
+	cmd.fs.StringVar(&cmd.logPolicy, "log-policy", "", "What happens once the output exceeds --log-limit: truncate, rotate or kill (default truncate)")
 
 	return cmd
 }
@@ -31,9 +67,44 @@ func (c *StartJobCommand) Run(ctx context.Context) ([]byte, error) {
 		return nil, errors.New("must provide command to start")
 	}
 
+	limits, err := c.resourceLimits()
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource limits: %w", err)
+	}
+
+	logLimit, err := c.logLimitRequest()
+	if err != nil {
+		return nil, fmt.Errorf("invalid log limit: %w", err)
+	}
+
+	// Variables given with --env override the ones from --env-file
+	var env []string
+	if c.envFile != "" {
+		if env, err = readEnvFile(c.envFile); err != nil {
+			return nil, err
+		}
+	}
+	env = append(env, c.env...)
+
 	req := pb.StartJobRequest{
-		Command:   c.fs.Args()[0],
-		Arguments: c.fs.Args()[1:],
+		Command:    c.fs.Args()[0],
+		Arguments:  c.fs.Args()[1:],
+		Limits:     limits,
+		Labels:     c.labels,
+		Env:        env,
+		WorkingDir: c.workDir,
+		InheritEnv: c.inheritEnv,
+		OpenStdin:  c.openStdin,
+		Tty:        c.tty,
+		LogLimit:   logLimit,
+	}
+
+	if c.timeout > 0 {
+		req.Timeout = durationpb.New(c.timeout)
+	}
+
+	if c.tty {
+		req.WindowSize = windowSize(int(os.Stdin.Fd()))
 	}
 
 	resp, err := c.client.StartJob(ctx, &req)
@@ -41,5 +112,69 @@ func (c *StartJobCommand) Run(ctx context.Context) ([]byte, error) {
 		return nil, fmt.Errorf("error starting job: %w", err)
 	}
 
-	return marshalPrintJobResponse(resp)
+	output, err := marshalPrintJobResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// The job is kept so it can be queried and deleted, but the command
+	// never ran
+	if resp.Status == pb.JobStatus_jobFailedToStart {
+		return output, fmt.Errorf("job %s failed to start: %s", resp.JobId, resp.ErrorMessage)
+	}
+
+	return output, nil
+}
+
+// Converts the human readable limit flags to a ResourceLimits message
+func (c *StartJobCommand) resourceLimits() (*pb.ResourceLimits, error) {
+	memory, err := parseBytes(c.memory)
+	if err != nil {
+		return nil, fmt.Errorf("bad --memory value: %w", err)
+	}
+
+	ioBps, err := parseBytes(c.ioBps)
+	if err != nil {
+		return nil, fmt.Errorf("bad --io-bps value: %w", err)
+	}
+
+	return &pb.ResourceLimits{
+		Cpus:          c.cpus,
+		MemoryBytes:   memory,
+		IoBytesPerSec: ioBps,
+		IoLimits:      c.ioLimits,
+	}, nil
+}
+
+// Converts the log limit flags to a LogLimit message
+func (c *StartJobCommand) logLimitRequest() (*pb.LogLimit, error) {
+	maxBytes, err := parseBytes(c.logLimit)
+	if err != nil {
+		return nil, fmt.Errorf("bad --log-limit value: %w", err)
+	}
+
+	req := &pb.LogLimit{MaxBytes: maxBytes}
+
+	if c.logPolicy != "" {
+		if req.Policy, err = parseLogPolicy(c.logPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// parseLogPolicy accepts both the api names (logRotate) and the short
+// names (rotate) of a policy, regardless of case.
+func parseLogPolicy(name string) (pb.LogPolicy, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	for value, policyName := range pb.LogPolicy_name {
+		policyName = strings.ToLower(policyName)
+		if name == policyName || "log"+name == policyName {
+			return pb.LogPolicy(value), nil
+		}
+	}
+
+	return pb.LogPolicy_logTruncate, fmt.Errorf("unknown log policy %q", name)
 }
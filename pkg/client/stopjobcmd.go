@@ -6,10 +6,15 @@ import (
 	"fmt"
 	pb "jobworker/pkg/api"
 	"log"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 type StopJobCommand struct {
 	*commonCommand
+	signal string
+	grace  time.Duration
 }
 
 func NewStopJobCommand() *StopJobCommand {
@@ -20,6 +25,9 @@ func NewStopJobCommand() *StopJobCommand {
 	}
 
 	cmd.addCommonFlags()
+	cmd.fs.StringVar(&cmd.signal, "signal", "", "Signal that asks the job to stop, e.g INT (default TERM)")
+	cmd.fs.DurationVar(&cmd.grace, "grace", 0, "Time the job has to exit before it is killed, e.g 10s (default set by the server)")
+
 	return cmd
 }
 
@@ -30,10 +38,19 @@ func (c *StopJobCommand) Run(ctx context.Context) ([]byte, error) {
 		return nil, fmt.Errorf("missing argument jobId")
 	}
 
-	req := pb.JobRequest{
-		JobId: c.fs.Args()[0],
+	req := pb.StopJobRequest{
+		JobId:  c.fs.Args()[0],
+		Signal: c.signal,
 	}
 
+	// A zero grace period kills the job immediately, so it is only sent
+	// if it was given explicitly
+	c.fs.Visit(func(f *flag.Flag) {
+		if f.Name == "grace" {
+			req.GracePeriod = durationpb.New(c.grace)
+		}
+	})
+
 	resp, err := c.client.StopJob(ctx, &req)
 	if err != nil {
 		return nil, fmt.Errorf("error stopping job: %w", err)
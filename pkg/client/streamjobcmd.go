@@ -1,38 +1,102 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"io"
 	pb "jobworker/pkg/api"
 	"log"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type StreamJobCommand struct {
 	*commonCommand
+	stdoutOnly bool
+	stderrOnly bool
+	offset     int64
+	tailLines  int
+	since      string
+	follow     bool
+	noFollow   bool
+	timestamps bool
+	stdout     io.Writer
+	stderr     io.Writer
 }
 
+// NewStreamJobCommand follows the job's output until it stops, unless
+// -no-follow is set
 func NewStreamJobCommand() *StreamJobCommand {
+	cmd := newOutputCommand("stream")
+	cmd.follow = true
+	cmd.fs.BoolVar(&cmd.noFollow, "no-follow", false, "Stop once the output written so far was received")
+	return cmd
+}
+
+// NewLogsCommand prints the output the job wrote so far, unless -follow is
+// set
+func NewLogsCommand() *StreamJobCommand {
+	cmd := newOutputCommand("logs")
+	cmd.fs.BoolVar(&cmd.follow, "follow", false, "Keep on printing the job's output until it stops")
+	return cmd
+}
+
+// Sets up the flags shared by the commands that print the job's output
+func newOutputCommand(name string) *StreamJobCommand {
 	cmd := &StreamJobCommand{
 		commonCommand: &commonCommand{
-			fs: flag.NewFlagSet("stream", flag.ExitOnError),
+			fs: flag.NewFlagSet(name, flag.ExitOnError),
 		},
+		stdout: os.Stdout,
+		stderr: os.Stderr,
 	}
 
 	cmd.addCommonFlags()
+	cmd.fs.BoolVar(&cmd.stdoutOnly, "stdout", false, "Print the job's stdout only")
+	cmd.fs.BoolVar(&cmd.stderrOnly, "stderr", false, "Print the job's stderr only")
+	cmd.fs.Int64Var(&cmd.offset, "offset", 0, "Start at this offset of the output, e.g to resume a stream")
+	cmd.fs.IntVar(&cmd.tailLines, "tail", 0, "Start at the last lines of the output")
+	cmd.fs.StringVar(&cmd.since, "since", "", "Start at the output written since a timestamp or a duration ago (e.g 10m)")
+	cmd.fs.BoolVar(&cmd.timestamps, "timestamps", false, "Prefix every line with the time it was captured at")
 	return cmd
 }
 
+// Run:
+// - Streams the requested output streams of the job, both by default
+// - Writes the job's stdout and stderr to the local stdout and stderr, as
+// is unless timestamps were requested
+// - Returns all of the output that was written
+// - Reports the offset the stream can be resumed from if it breaks
 func (c *StreamJobCommand) Run(ctx context.Context) ([]byte, error) {
-	log.Printf("Executing stream command with args=%v", c.fs.Args())
+	log.Printf("Executing %s command with args=%v", c.Name(), c.fs.Args())
 
 	if len(c.fs.Args()) == 0 {
 		return nil, fmt.Errorf("missing argument jobId")
 	}
 
+	since, err := parseSince(c.since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	req := pb.JobRequest{
-		JobId: c.fs.Args()[0],
+		JobId:     c.fs.Args()[0],
+		Offset:    c.offset,
+		TailLines: int32(c.tailLines),
+		NoFollow:  !c.follow || c.noFollow,
+	}
+	if c.stdoutOnly {
+		req.Streams = append(req.Streams, pb.OutputStream_outputStdout)
+	}
+	if c.stderrOnly {
+		req.Streams = append(req.Streams, pb.OutputStream_outputStderr)
+	}
+	if !since.IsZero() {
+		req.Since = timestamppb.New(since)
 	}
 
 	stream, err := c.client.StreamJob(ctx, &req)
@@ -41,6 +105,13 @@ func (c *StreamJobCommand) Run(ctx context.Context) ([]byte, error) {
 	}
 
 	var output []byte
+	next := c.offset
+
+	// Lines are timestamped separately for each stream
+	writers := map[pb.OutputStream]*outputWriter{
+		pb.OutputStream_outputStdout: {out: c.stdout, timestamps: c.timestamps, lineStart: true},
+		pb.OutputStream_outputStderr: {out: c.stderr, timestamps: c.timestamps, lineStart: true},
+	}
 
 	for {
 		resp, err := stream.Recv()
@@ -48,13 +119,57 @@ func (c *StreamJobCommand) Run(ctx context.Context) ([]byte, error) {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error while receiving data: %w", err)
+			return nil, fmt.Errorf("error while receiving data, resume with -offset %d: %w", next, err)
 		}
+		next = resp.Offset + int64(len(resp.Message))
 
-		fmt.Printf("%q", resp.Message)
+		writer, ok := writers[resp.Stream]
+		if !ok {
+			writer = writers[pb.OutputStream_outputStdout]
+		}
+
+		data := writer.format(resp)
+		if _, err := writer.out.Write(data); err != nil {
+			return nil, fmt.Errorf("error writing output: %w", err)
+		}
 
-		output = append(output, resp.Message...)
+		output = append(output, data...)
 	}
 
 	return output, nil
 }
+
+// outputWriter writes one of the job's streams locally
+type outputWriter struct {
+	out        io.Writer
+	timestamps bool
+	// lineStart is true if the next output starts a new line
+	lineStart bool
+}
+
+// format returns the message as is, or with the time it was captured at
+// before every line if timestamps were requested
+func (w *outputWriter) format(resp *pb.StreamJobResponse) []byte {
+	if !w.timestamps || len(resp.Message) == 0 {
+		return resp.Message
+	}
+
+	prefix := "- "
+	if resp.Time != nil {
+		prefix = resp.Time.AsTime().Format(time.RFC3339Nano) + " "
+	}
+
+	var data []byte
+	for _, line := range bytes.SplitAfter(resp.Message, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if w.lineStart {
+			data = append(data, prefix...)
+		}
+		data = append(data, line...)
+		w.lineStart = line[len(line)-1] == '\n'
+	}
+
+	return data
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"fmt"
+	pb "jobworker/pkg/api"
+
+	"golang.org/x/sys/unix"
+)
+
+// isTerminal returns true if fd refers to a terminal
+func isTerminal(fd int) bool {
+	_, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	return err == nil
+}
+
+// makeRaw:
+// - Puts the local terminal in raw mode, so keys such as Ctrl-C reach the
+// job's terminal instead of the client.
+// - Returns a function that restores the previous state.
+func makeRaw(fd int) (func() error, error) {
+	termios, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting terminal state: %w", err)
+	}
+
+	oldState := *termios
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+
+	if err := unix.IoctlSetTermios(fd, unix.TCSETS, termios); err != nil {
+		return nil, fmt.Errorf("failed setting terminal to raw mode: %w", err)
+	}
+
+	return func() error {
+		return unix.IoctlSetTermios(fd, unix.TCSETS, &oldState)
+	}, nil
+}
+
+// windowSize returns the size of the local terminal, or nil if fd is not
+// a terminal
+func windowSize(fd int) *pb.WindowSize {
+	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return nil
+	}
+
+	return &pb.WindowSize{
+		Rows: uint32(ws.Row),
+		Cols: uint32(ws.Col),
+	}
+}
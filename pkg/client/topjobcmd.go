@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	pb "jobworker/pkg/api"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\x1b[H\x1b[2J"
+
+type TopJobCommand struct {
+	*commonCommand
+	interval time.Duration
+	stdout   io.Writer
+	// refresh redraws every sample over the previous one, instead of
+	// printing them one after another
+	refresh bool
+}
+
+func NewTopJobCommand() *TopJobCommand {
+	cmd := &TopJobCommand{
+		commonCommand: &commonCommand{
+			fs: flag.NewFlagSet("top", flag.ExitOnError),
+		},
+		stdout:  os.Stdout,
+		refresh: isTerminal(int(os.Stdout.Fd())),
+	}
+
+	cmd.addCommonFlags()
+	cmd.fs.DurationVar(&cmd.interval, "interval", time.Second, "How often the job's resources are sampled")
+	return cmd
+}
+
+// Run:
+// - Streams samples of the job's resources until it stops
+// - Redraws the view for every sample if stdout is a terminal, otherwise
+// prints the samples one after another
+// - Returns the last view that was printed
+func (c *TopJobCommand) Run(ctx context.Context) ([]byte, error) {
+	log.Printf("Executing top command with args=%v", c.fs.Args())
+
+	if len(c.fs.Args()) == 0 {
+		return nil, fmt.Errorf("missing argument jobId")
+	}
+
+	jobID := c.fs.Args()[0]
+	req := pb.JobMetricsRequest{
+		JobId:    jobID,
+		Interval: durationpb.New(c.interval),
+	}
+
+	stream, err := c.client.StreamJobMetrics(ctx, &req)
+	if err != nil {
+		return nil, fmt.Errorf("error streaming metrics: %w", err)
+	}
+
+	var view string
+	for {
+		metrics, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error while receiving metrics: %w", err)
+		}
+
+		view = renderMetrics(jobID, metrics)
+		if c.refresh {
+			fmt.Fprint(c.stdout, clearScreen+view)
+		} else {
+			fmt.Fprintln(c.stdout, view)
+		}
+	}
+
+	return []byte(view), nil
+}
+
+// renderMetrics formats a sample of the job's resources as a table
+func renderMetrics(jobID string, metrics *pb.JobMetrics) string {
+	var view strings.Builder
+
+	fmt.Fprintf(&view, "Job %s at %s\n\n", jobID, metrics.Time.AsTime().Local().Format(time.TimeOnly))
+	fmt.Fprintf(&view, "%-8s %.1f%%\n", "CPU", metrics.CpuPercent)
+	fmt.Fprintf(&view, "%-8s %s (peak %s)\n", "Memory",
+		formatBytes(float64(metrics.MemoryCurrentBytes)), formatBytes(float64(metrics.MemoryPeakBytes)))
+	fmt.Fprintf(&view, "%-8s read %s/s (%.0f iops), write %s/s (%.0f iops)\n", "IO",
+		formatBytes(metrics.ReadBytesPerSec), metrics.ReadIops, formatBytes(metrics.WriteBytesPerSec), metrics.WriteIops)
+	fmt.Fprintf(&view, "%-8s %d\n\n", "Pids", metrics.PidsCurrent)
+
+	fmt.Fprintf(&view, "%-8s %8s %8s %8s %8s\n", "Pressure", "some10", "some60", "full10", "full60")
+	pressures := []struct {
+		name     string
+		pressure *pb.Pressure
+	}{
+		{"cpu", metrics.CpuPressure},
+		{"memory", metrics.MemoryPressure},
+		{"io", metrics.IoPressure},
+	}
+	for _, p := range pressures {
+		some, full := p.pressure.GetSome(), p.pressure.GetFull()
+		fmt.Fprintf(&view, "%-8s %7.2f%% %7.2f%% %7.2f%% %7.2f%%\n", p.name,
+			some.GetAvg10(), some.GetAvg60(), full.GetAvg10(), full.GetAvg60())
+	}
+
+	return view.String()
+}
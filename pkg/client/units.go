@@ -0,0 +1,86 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// byteUnits maps a size suffix to its multiplier, all units are powers
+// of 1024 so "1M", "1MB" and "1MiB" are the same size.
+var byteUnits = map[string]int64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// parseBytes converts a human readable size such as "512M" or "1.5G" to bytes.
+func parseBytes(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	idx := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if idx < 0 {
+		idx = len(value)
+	}
+
+	// Strip the optional "B" and "iB" endings, leaving only the unit
+	unit := strings.ToUpper(strings.TrimSpace(value[idx:]))
+	unit = strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "I")
+
+	multiplier, ok := byteUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit in %q", value)
+	}
+
+	size, err := strconv.ParseFloat(value[:idx], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", value, err)
+	}
+
+	return int64(size * float64(multiplier)), nil
+}
+
+// formatBytes renders a size with the largest unit it has at least one
+// of, e.g "1.5G"
+func formatBytes(size float64) string {
+	units := []string{"", "K", "M", "G", "T"}
+
+	unit := 0
+	for unit < len(units)-1 && size >= float64(byteUnits[units[unit+1]]) {
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%.0fB", size)
+	}
+
+	return fmt.Sprintf("%.1f%s", size/float64(byteUnits[units[unit]]), units[unit])
+}
+
+// parseSince converts either a timestamp such as "2024-01-02T15:04:05Z" or
+// a duration before now such as "10m" to a time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+
+	ago, err := time.ParseDuration(value)
+	if err != nil || ago < 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected a timestamp or a duration", value)
+	}
+
+	return now.Add(-ago), nil
+}
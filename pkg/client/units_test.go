@@ -0,0 +1,87 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]int64{
+		"":       0,
+		"1024":   1024,
+		"10B":    10,
+		"1K":     1 << 10,
+		"512M":   512 << 20,
+		"512MB":  512 << 20,
+		"512MiB": 512 << 20,
+		"1.5G":   3 << 29,
+		"2t":     2 << 40,
+	}
+
+	for value, expected := range tests {
+		size, err := parseBytes(value)
+		if err != nil {
+			t.Fatalf("parseBytes(%q) failed: %v", value, err)
+		}
+		if size != expected {
+			t.Fatalf("parseBytes(%q) = %d, expected %d", value, size, expected)
+		}
+	}
+
+	for _, value := range []string{"M", "10X", "1.2.3K", "-1M"} {
+		if _, err := parseBytes(value); err == nil {
+			t.Fatalf("parseBytes(%q) should have failed", value)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[float64]string{
+		0:               "0B",
+		1023:            "1023B",
+		1 << 10:         "1.0K",
+		3 << 29:         "1.5G",
+		5.5 * (1 << 20): "5.5M",
+		2 << 50:         "2048.0T",
+	}
+
+	for size, expected := range tests {
+		if formatted := formatBytes(size); formatted != expected {
+			t.Fatalf("formatBytes(%v) = %q, expected %q", size, formatted, expected)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := map[string]time.Time{
+		"":                          {},
+		"10m":                       now.Add(-10 * time.Minute),
+		"1h30m":                     now.Add(-90 * time.Minute),
+		"2024-01-01T00:00:00Z":      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2024-01-01T02:00:00+02:00": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for value, expected := range tests {
+		since, err := parseSince(value, now)
+		if err != nil {
+			t.Fatalf("parseSince(%q) failed: %v", value, err)
+		}
+		if !since.Equal(expected) {
+			t.Fatalf("parseSince(%q) = %v, expected %v", value, since, expected)
+		}
+	}
+
+	for _, value := range []string{"yesterday", "-5m", "2024-01-01"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Fatalf("parseSince(%q) should have failed", value)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	pb "jobworker/pkg/api"
+	"log"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type WatchJobCommand struct {
+	*commonCommand
+}
+
+func NewWatchJobCommand() *WatchJobCommand {
+	cmd := &WatchJobCommand{
+		commonCommand: &commonCommand{
+			fs: flag.NewFlagSet("watch", flag.ExitOnError),
+		},
+	}
+
+	cmd.addCommonFlags()
+	return cmd
+}
+
+// Run:
+// - Prints the job's state as a json line every time its status changes
+// - Returns once the job stops, the output ends with its final state
+func (c *WatchJobCommand) Run(ctx context.Context) ([]byte, error) {
+	log.Printf("Executing watch command with args=%v", c.fs.Args())
+
+	if len(c.fs.Args()) == 0 {
+		return nil, fmt.Errorf("missing argument jobId")
+	}
+
+	req := pb.JobRequest{
+		JobId: c.fs.Args()[0],
+	}
+
+	stream, err := c.client.WatchJob(ctx, &req)
+	if err != nil {
+		return nil, fmt.Errorf("error watching job: %w", err)
+	}
+
+	var output []byte
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error while receiving data: %w", err)
+		}
+
+		data, err := protojson.Marshal(resp)
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal response: %w", err)
+		}
+
+		fmt.Println(string(data))
+
+		output = append(output, data...)
+		output = append(output, '\n')
+	}
+
+	return output, nil
+}
@@ -0,0 +1,220 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sys/unix"
+)
+
+// procStatStartTimeField is the index of starttime in /proc/$pid/stat,
+// counting from the state field which follows the command
+const procStatStartTimeField = 19
+
+// readProcStat returns the fields of /proc/$pid/stat following the
+// command, which may hold spaces and parentheses, so the first field is
+// the process state.
+func readProcStat(pid string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(procPath, pid, "stat"))
+	if err != nil {
+		return nil, err
+	}
+
+	// /proc/$pid/stat looks like:
+	// pid (comm) state ppid pgrp ...
+	stat := string(data)
+	return strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:]), nil
+}
+
+// processStartTime returns the time the process started at in clock ticks
+// since boot, which tells it apart from a later process with the same pid.
+func processStartTime(pid int) (uint64, error) {
+	fields, err := readProcStat(strconv.Itoa(pid))
+	if err != nil {
+		return 0, fmt.Errorf("failed reading stat of pid %d: %w", pid, err)
+	}
+
+	if len(fields) <= procStatStartTimeField {
+		return 0, fmt.Errorf("unexpected stat format of pid %d", pid)
+	}
+
+	return strconv.ParseUint(fields[procStatStartTimeField], 10, 64)
+}
+
+// adopt:
+//   - Takes over a job that was running when the previous manager went
+//     away, if its process is still alive.
+//   - The pid is only trusted if the process start time matches, since it
+//     may have been reused.
+//   - Returns false if the job's process is gone.
+func (j *Job) adopt() bool {
+	pid := int(j.ProcessID())
+	if pid <= 0 || j.procStartTime == 0 {
+		return false
+	}
+
+	startTime, err := processStartTime(pid)
+	if err != nil || startTime != j.procStartTime {
+		return false
+	}
+
+	pidfd, err := unix.PidfdOpen(pid, 0)
+	if err != nil {
+		log.Printf("Failed opening pidfd for job %s (pid=%d): %v", j.jobID, pid, err)
+		return false
+	}
+
+	log.Printf("Adopting job %s (pid=%d)", j.jobID, pid)
+
+	if err := j.reopenOutput(); err != nil {
+		log.Printf("Output of job %s is lost: %v", j.jobID, err)
+	}
+
+	j.exited = make(chan struct{})
+	go j.monitorAdopted(pidfd)
+
+	if deadline := j.Deadline(); !deadline.IsZero() {
+		go j.enforceTimeout(deadline)
+	}
+
+	return true
+}
+
+// monitorAdopted:
+//   - Runs in a goroutine, like monitorCommand for jobs we started.
+//   - The process is not our child so its exit status is unknown, we only
+//     learn that it exited once its pidfd becomes readable.
+func (j *Job) monitorAdopted(pidfd int) {
+	defer unix.Close(pidfd)
+
+	fds := []unix.PollFd{{Fd: int32(pidfd), Events: unix.POLLIN}}
+	for {
+		_, err := unix.Poll(fds, -1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			log.Printf("Polling pidfd of job %s failed: %v", j.jobID, err)
+		}
+		break
+	}
+
+	j.finishedAt.Store(time.Now().UnixNano())
+	j.exitCode.Store(-1)
+
+	log.Printf("Adopted job %s exited", j.jobID)
+
+	j.stopMu.Lock()
+	close(j.exited)
+	j.setAdoptedTerminationReason()
+	j.stopMu.Unlock()
+
+	if deadline := j.stopDeadline.Load(); deadline != 0 {
+		j.reapProcesses(time.Unix(0, deadline))
+	}
+
+	if err := j.closeOutput(); err != nil {
+		log.Printf("Job %s: %v", j.jobID, err)
+	}
+
+	err := j.stop(JobRunning, JobStopped)
+	log.Printf("Job stop for %s returned %v", j.jobID, err)
+}
+
+// setAdoptedTerminationReason is setTerminationReason for adopted jobs,
+// without an exit status they are lost unless we know why they ended.
+func (j *Job) setAdoptedTerminationReason() {
+	reason := TerminationLost
+
+	if j.oomKilled() {
+		reason = TerminationOOMKilled
+	}
+
+	if j.stopDeadline.Load() != 0 {
+		reason = TerminationStopped
+	}
+
+	if j.logLimitExceeded() {
+		reason = TerminationLogLimitExceeded
+	}
+
+	if j.timedOut.Load() {
+		reason = TerminationTimedOut
+	}
+
+	j.reason.Store(int32(reason))
+}
+
+// cleanupStaleCgroups:
+//   - Scans the cgroup root for job cgroups, which are named by their uuid.
+//   - Removes the ones that don't belong to an active job, processes left
+//     in the cgroup of a job that is known to be dead are killed first.
+//   - Cgroups of unknown jobs are only removed if they are empty.
+func (m *JobManager) cleanupStaleCgroups() error {
+	entries, err := os.ReadDir(m.cgroupRoot)
+	if err != nil {
+		return fmt.Errorf("failed reading cgroup root %s: %w", m.cgroupRoot, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := uuid.Parse(entry.Name()); err != nil {
+			continue
+		}
+
+		known := false
+		if j, ok := m.jobDB.Load(entry.Name()); ok {
+			if job, ok := j.(*Job); ok {
+				if job.isActive() {
+					continue
+				}
+				known = true
+			}
+		}
+
+		cgroup := NewCgroup(m.cgroupRoot, entry.Name())
+		if err := m.removeStaleCgroup(cgroup, known); err != nil {
+			log.Printf("Failed removing stale cgroup %s: %v", cgroup.path, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *JobManager) removeStaleCgroup(cgroup *Cgroup, known bool) error {
+	populated, err := cgroup.Populated()
+	if err != nil {
+		return err
+	}
+
+	if populated {
+		if !known {
+			log.Printf("Leaving cgroup %s of an unknown job with live processes", cgroup.path)
+			return nil
+		}
+
+		log.Printf("Killing processes left in cgroup %s", cgroup.path)
+
+		if err := cgroup.Kill(); err != nil {
+			return err
+		}
+
+		timeout := time.Now().Add(killTimeout)
+		for populated && time.Now().Before(timeout) {
+			time.Sleep(stopPollInterval)
+			if populated, err = cgroup.Populated(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return cgroup.Delete()
+}
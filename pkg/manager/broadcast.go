@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// broadcastBufferBytes and broadcastBufferChunks bound the output of a
+	// running job that is kept in memory for its streams
+	broadcastBufferBytes  = 4 << 20
+	broadcastBufferChunks = 4 << 10
+)
+
+// outputBroadcaster fans out the output of a running job to its streams.
+// The latest output is kept in a ring buffer, so streams that keep up with
+// the job never read its log.  Streams that fall behind the buffer read the
+// output that left it from the log, where it was written first.
+type outputBroadcaster struct {
+	mu sync.RWMutex
+	// chunks is the ring buffer, holding count chunks from head on
+	chunks   []OutputChunk
+	head     int
+	count    int
+	size     int64
+	maxBytes int64
+	// end is the offset of the output that follows the buffered output
+	end int64
+	// wake is closed and replaced whenever output is published, it is nil
+	// once the broadcaster is closed
+	wake chan struct{}
+}
+
+// newOutputBroadcaster creates a broadcaster for output that starts at
+// offset
+func newOutputBroadcaster(offset int64) *outputBroadcaster {
+	return &outputBroadcaster{
+		chunks:   make([]OutputChunk, broadcastBufferChunks),
+		maxBytes: broadcastBufferBytes,
+		end:      offset,
+		wake:     make(chan struct{}),
+	}
+}
+
+// publish:
+//   - Adds a copy of data to the buffer, after the output it already holds.
+//   - Evicts the oldest output once the buffer is full.
+//   - Wakes the streams that wait for output.
+func (b *outputBroadcaster) publish(stream OutputStream, data []byte, offset int64, now time.Time) {
+	chunk := OutputChunk{
+		Stream: stream,
+		Data:   append([]byte(nil), data...),
+		Offset: offset,
+		Time:   now,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.wake == nil {
+		return
+	}
+
+	for b.count > 0 && (b.count == len(b.chunks) || b.size+int64(len(chunk.Data)) > b.maxBytes) {
+		b.size -= int64(len(b.chunks[b.head].Data))
+		b.chunks[b.head] = OutputChunk{}
+		b.head = (b.head + 1) % len(b.chunks)
+		b.count--
+	}
+
+	b.chunks[(b.head+b.count)%len(b.chunks)] = chunk
+	b.count++
+	b.size += int64(len(chunk.Data))
+	b.end = offset + int64(len(chunk.Data))
+
+	close(b.wake)
+	b.wake = make(chan struct{})
+}
+
+// read:
+//   - Appends the buffered chunks that end after offset to chunks.
+//   - Returns false if some of the output that follows offset already left
+//     the buffer, it should be read from the log instead.
+//   - Returns a channel which is closed once there's more output, or nil
+//     once the broadcaster is closed.
+func (b *outputBroadcaster) read(offset int64, chunks []OutputChunk) ([]OutputChunk, <-chan struct{}, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	start := b.end
+	if b.count > 0 {
+		start = b.chunks[b.head].Offset
+	}
+	if offset < start {
+		return chunks, b.wake, false
+	}
+
+	// The buffered output is ordered by offset
+	first := sort.Search(b.count, func(i int) bool {
+		chunk := b.chunks[(b.head+i)%len(b.chunks)]
+		return chunk.Offset+int64(len(chunk.Data)) > offset
+	})
+	for i := first; i < b.count; i++ {
+		chunks = append(chunks, b.chunks[(b.head+i)%len(b.chunks)])
+	}
+
+	return chunks, b.wake, true
+}
+
+// close wakes the streams up for the last time, they end once they sent
+// the buffered output
+func (b *outputBroadcaster) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.wake != nil {
+		close(b.wake)
+		b.wake = nil
+	}
+}
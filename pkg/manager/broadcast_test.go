@@ -0,0 +1,213 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// newTestLog creates a log which publishes its output to a broadcaster
+// that buffers up to bufferBytes
+func newTestLog(t *testing.T, maxBytes int64, bufferBytes int64) *logWriter {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "job.log")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed creating log: %v", err)
+	}
+
+	if _, err := file.Write(appendLogHeader(nil, 0)); err != nil {
+		t.Fatalf("failed writing log header: %v", err)
+	}
+
+	broadcaster := newOutputBroadcaster(0)
+	broadcaster.maxBytes = bufferBytes
+
+	return &logWriter{
+		path:        path,
+		file:        file,
+		size:        int64(logHeaderSize),
+		maxBytes:    maxBytes,
+		policy:      LogRotate,
+		full:        func() {},
+		broadcaster: broadcaster,
+	}
+}
+
+func readOutput(outChannel <-chan OutputChunk) (string, error) {
+	var output strings.Builder
+	var offset int64
+
+	for chunk := range outChannel {
+		if chunk.Offset < offset {
+			return "", fmt.Errorf("chunk at offset %d after offset %d", chunk.Offset, offset)
+		}
+		offset = chunk.Offset + int64(len(chunk.Data))
+		output.Write(chunk.Data)
+	}
+
+	return output.String(), nil
+}
+
+func TestOutputBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	broadcaster := newOutputBroadcaster(10)
+	broadcaster.maxBytes = 8
+
+	chunks, wake, ok := broadcaster.read(10, nil)
+	if len(chunks) != 0 || wake == nil || !ok {
+		t.Fatalf("expected an empty buffer, received %v/%v", chunks, ok)
+	}
+
+	broadcaster.publish(OutputStdout, []byte("abcd"), 10, time.Now())
+	select {
+	case <-wake:
+	default:
+		t.Fatalf("expected publishing to wake the streams up")
+	}
+
+	broadcaster.publish(OutputStderr, []byte("efgh"), 14, time.Now())
+	broadcaster.publish(OutputStdout, []byte("ij"), 18, time.Now())
+
+	// The first chunk was evicted to make room for the last one
+	if _, _, ok := broadcaster.read(12, nil); ok {
+		t.Fatalf("expected evicted output to be read from the log")
+	}
+
+	chunks, _, ok = broadcaster.read(15, nil)
+	if !ok || len(chunks) != 2 || chunks[0].Offset != 14 || chunks[1].Offset != 18 {
+		t.Fatalf("expected the chunks that follow the offset, received %+v", chunks)
+	}
+
+	broadcaster.close()
+	if chunks, wake, ok := broadcaster.read(20, nil); len(chunks) != 0 || wake != nil || !ok {
+		t.Fatalf("expected a closed broadcaster, received %v/%v", chunks, wake)
+	}
+}
+
+func TestWatchLog(t *testing.T) {
+	t.Parallel()
+
+	w := newTestLog(t, 0, broadcastBufferBytes)
+
+	expected := ""
+	for i := 1; i <= 5; i++ {
+		expected += fmt.Sprintf("%d\n", i)
+	}
+
+	go func() {
+		for i := 1; i <= 5; i++ {
+			fmt.Fprintf(w.stream(OutputStdout), "%d\n", i)
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Close()
+	}()
+
+	// Streams that start late read the beginning of the output from the log
+	var errGrp errgroup.Group
+	for i := 0; i < 5; i++ {
+		delay := time.Duration(i) * 200 * time.Millisecond
+		errGrp.Go(func() error {
+			time.Sleep(delay)
+
+			w.mu.Lock()
+			outChannel, err := watchLog(context.Background(), w.path, withBroadcaster(w.broadcaster))
+			w.mu.Unlock()
+			if err != nil {
+				return fmt.Errorf("failed watching log: %w", err)
+			}
+
+			output, err := readOutput(outChannel)
+			if err != nil {
+				return err
+			}
+			if output != expected {
+				return fmt.Errorf("wrong output, expected=[%s], received=[%s]", expected, output)
+			}
+			return nil
+		})
+	}
+
+	if err := errGrp.Wait(); err != nil {
+		t.Fatalf("streams failed: %v", err)
+	}
+}
+
+func TestWatchLogCatchUp(t *testing.T) {
+	t.Parallel()
+
+	// The buffer only holds a few lines, and the log is rotated
+	w := newTestLog(t, 64<<10, 64)
+
+	w.mu.Lock()
+	outChannel, err := watchLog(context.Background(), w.path, withBroadcaster(w.broadcaster))
+	w.mu.Unlock()
+	if err != nil {
+		t.Fatalf("failed watching log: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 5000; i++ {
+			fmt.Fprintf(w.stream(OutputStdout), "%d\n", i)
+		}
+		w.Close()
+	}()
+
+	// The stream falls behind the buffer until all of the output was written
+	<-done
+
+	output, err := readOutput(outChannel)
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+
+	// Output that was rotated away before it was read is lost
+	if !strings.HasSuffix(output, "4999\n5000\n") {
+		t.Fatalf("expected the stream to end with the last lines, received %d bytes", len(output))
+	}
+}
+
+func TestWatchLogCancel(t *testing.T) {
+	t.Parallel()
+
+	w := newTestLog(t, 0, broadcastBufferBytes)
+	defer w.Close()
+
+	// More chunks than the channel holds, so the stream blocks sending them
+	for i := 1; i <= 2*outputChannelSize; i++ {
+		fmt.Fprintf(w.stream(OutputStdout), "%d\n", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w.mu.Lock()
+	outChannel, err := watchLog(ctx, w.path, withBroadcaster(w.broadcaster))
+	w.mu.Unlock()
+	if err != nil {
+		t.Fatalf("failed watching log: %v", err)
+	}
+
+	for len(outChannel) < outputChannelSize {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	received := 0
+	for range outChannel {
+		received++
+	}
+
+	if received >= 2*outputChannelSize {
+		t.Fatalf("expected the stream to end once it was cancelled, received %d chunks", received)
+	}
+}
@@ -1,7 +1,7 @@
 package manager
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -13,21 +13,69 @@ import (
 )
 
 const (
-	cpuMaxMicroSec     = 1_000_000
-	ioMaxMountPoint    = "/"
-	procMountsPath     = "/proc/self/mounts"
-	cgroupDirPerm      = 0o755
-	cgroupFilePerm     = 0o644
-	numProcMountFields = 6
+	cpuMaxMicroSec      = 1_000_000
+	cpuMinQuotaMicroSec = 1_000
+	ioMaxMountPoint     = "/"
+	cgroupDirPerm       = 0o755
+	cgroupFilePerm      = 0o644
 )
 
+// ResourceLimits describes the cgroup limits of a job, a zero value
+// means that the limit is not set.
 type ResourceLimits struct {
 	CPUMaxQuotaMicroSec int64
 	MemMaxBytes         int64
-	IOMaxBytesPerSec    int64
+	// IOMaxBytesPerSec limits both reads and writes of the disk that backs
+	// `ioMaxMountPoint`
+	IOMaxBytesPerSec int64
+	// IOLimits limit the io of specific devices, they take precedence over
+	// IOMaxBytesPerSec
+	IOLimits []IOLimit
+}
+
+// DefaultResourceLimits returns the limits used for jobs that did not
+// request specific ones.
+func DefaultResourceLimits() ResourceLimits {
+	return ResourceLimits{
+		CPUMaxQuotaMicroSec: jobWorkerCPUMaxQuotaMicroSec,
+		MemMaxBytes:         jobWorkerMemMaxBytes,
+		IOMaxBytesPerSec:    jobWorkerIoMaxBps,
+	}
+}
+
+// CPUs returns the cpu limit as a (possibly fractional) number of cpus.
+func (l ResourceLimits) CPUs() float64 {
+	return float64(l.CPUMaxQuotaMicroSec) / cpuMaxMicroSec
+}
+
+// Validate makes sure the limits can be written to the cgroup files.
+func (l ResourceLimits) Validate() error {
+	if l.CPUMaxQuotaMicroSec < 0 || l.MemMaxBytes < 0 || l.IOMaxBytesPerSec < 0 {
+		return fmt.Errorf("resource limits cannot be negative")
+	}
+
+	if l.CPUMaxQuotaMicroSec > 0 && l.CPUMaxQuotaMicroSec < cpuMinQuotaMicroSec {
+		return fmt.Errorf("cpu quota %dus is below the minimum of %dus", l.CPUMaxQuotaMicroSec, cpuMinQuotaMicroSec)
+	}
+
+	// Unknown devices are rejected before the job starts
+	if _, err := l.resolveIOLimits(hostBlockDevices); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CPUQuotaForCPUs converts a number of cpus (e.g 1.5) to a cpu.max quota
+// using the fixed `cpuMaxMicroSec` period.
+func CPUQuotaForCPUs(cpus float64) int64 {
+	return int64(cpus * cpuMaxMicroSec)
 }
 
 type Cgroup struct {
+	// file holds fd open, the fd would be closed along with the file once
+	// it is garbage collected
+	file *os.File
 	fd   int
 	root string
 	path string
@@ -42,12 +90,12 @@ func NewCgroup(root, name string) *Cgroup {
 }
 
 // Create:
-// - Activates the cpu, memory and io controllers.
+// - Activates the cpu, memory, io and pids controllers.
 // - Mkdir /sys/fs/cgroup/$cgroup-name.
 // - Open a descriptor to the new cgroup.
 func (c *Cgroup) Create(limits *ResourceLimits) error {
 	// Make sure controllers are activated
-	controllers := "+cpu +memory +io"
+	controllers := "+cpu +memory +io +pids"
 	if err := writeToFilename(filepath.Join(c.root, "cgroup.subtree_control"), controllers); err != nil {
 		return fmt.Errorf("failed activating cgroup controllers: %w", err)
 	}
@@ -62,6 +110,7 @@ func (c *Cgroup) Create(limits *ResourceLimits) error {
 	if err != nil {
 		return fmt.Errorf("failed opening cgroup path %s: %w", c.path, err)
 	}
+	c.file = file
 	c.fd = int(file.Fd())
 
 	if err = c.setLimits(limits); err != nil {
@@ -75,10 +124,10 @@ func (c *Cgroup) Create(limits *ResourceLimits) error {
 // - Close the cgroup file descriptor.
 // - Delete the cgroup.
 func (c *Cgroup) Delete() error {
-	if c.fd > 0 {
+	if c.file != nil {
 		log.Print("Closing cgroup file descriptor")
 
-		if err := unix.Close(c.fd); err != nil {
+		if err := c.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
 			return fmt.Errorf("failed closing cgroup fd for %s: %w", c.path, err)
 		}
 	}
@@ -92,6 +141,103 @@ func (c *Cgroup) Delete() error {
 	return nil
 }
 
+// Signal sends sig to every process in the cgroup
+func (c *Cgroup) Signal(sig unix.Signal) error {
+	pids, err := c.processes()
+	if err != nil {
+		return err
+	}
+
+	for _, pid := range pids {
+		// The process may have exited since we read the list
+		if err := unix.Kill(pid, sig); err != nil && err != unix.ESRCH {
+			return fmt.Errorf("failed sending %v to pid %d: %w", sig, pid, err)
+		}
+	}
+
+	return nil
+}
+
+// Kill:
+// - Writes to cgroup.kill which kills the entire cgroup atomically, even
+// processes that fork while being killed.
+// - Falls back to SIGKILL for each process on kernels without cgroup.kill.
+func (c *Cgroup) Kill() error {
+	err := writeToFilename(filepath.Join(c.path, "cgroup.kill"), "1")
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("Killing cgroup %s failed (%v), killing each process", c.path, err)
+
+	return c.Signal(unix.SIGKILL)
+}
+
+// Populated returns true while there are live processes in the cgroup
+func (c *Cgroup) Populated() (bool, error) {
+	value, err := c.readKeyedValue("cgroup.events", "populated")
+	if err != nil {
+		return false, err
+	}
+
+	return value == "1", nil
+}
+
+// OOMKills returns the number of processes in the cgroup that were killed
+// by the OOM killer
+func (c *Cgroup) OOMKills() (int64, error) {
+	value, err := c.readKeyedValue("memory.events", "oom_kill")
+	if err != nil {
+		return 0, err
+	}
+
+	kills, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid oom_kill value %q: %w", value, err)
+	}
+
+	return kills, nil
+}
+
+// readKeyedValue returns the value of key from a flat keyed file such as
+// cgroup.events, which looks like:
+// populated 1
+// frozen 0
+func (c *Cgroup) readKeyedValue(filename, key string) (string, error) {
+	path := filepath.Join(c.path, filename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed reading %s: %w", path, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, found := strings.CutPrefix(line, key+" "); found {
+			return value, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s not found in %s", key, path)
+}
+
+func (c *Cgroup) processes() ([]int, error) {
+	path := filepath.Join(c.path, "cgroup.procs")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", path, err)
+	}
+
+	var pids []int
+	for _, field := range strings.Fields(string(data)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pid %q in %s: %w", field, path, err)
+		}
+		pids = append(pids, pid)
+	}
+
+	return pids, nil
+}
+
 func (c *Cgroup) setLimits(limits *ResourceLimits) error {
 	if limits.CPUMaxQuotaMicroSec > 0 {
 		if err := c.setCPULimit(limits.CPUMaxQuotaMicroSec); err != nil {
@@ -99,8 +245,13 @@ func (c *Cgroup) setLimits(limits *ResourceLimits) error {
 		}
 	}
 
-	if limits.IOMaxBytesPerSec > 0 {
-		if err := c.setDiskIOLimit(limits.IOMaxBytesPerSec); err != nil {
+	if limits.IOMaxBytesPerSec > 0 || len(limits.IOLimits) > 0 {
+		entries, err := limits.ioMaxEntries(hostBlockDevices)
+		if err != nil {
+			return fmt.Errorf("failed resolving io limits: %w", err)
+		}
+
+		if err := c.setIOLimits(entries); err != nil {
 			return fmt.Errorf("failed setting disk io limit: %w", err)
 		}
 	}
@@ -131,32 +282,6 @@ func (c *Cgroup) setMemoryLimit(limit int64) error {
 	return writeToFilename(filepath.Join(c.path, "memory.max"), value)
 }
 
-// setDiskIOLimit:
-// - Find the device for /.
-// - Find the device's major and minor numbers.
-// - Write rbps and wbps values to io.max file.
-func (c *Cgroup) setDiskIOLimit(limit int64) error {
-	device, err := getDeviceForMount(ioMaxMountPoint)
-	if err != nil {
-		return fmt.Errorf("failed getting device for mount: %w", err)
-	}
-
-	var stat unix.Stat_t
-	if err = unix.Stat(device, &stat); err != nil {
-		return fmt.Errorf("error calling stat on device %s: %w", device, err)
-	}
-
-	major := unix.Major(stat.Rdev)
-
-	log.Printf("Found device=%v, major=%v for %s", device, major, ioMaxMountPoint)
-
-	// We found the major and minor, but we will just use the major to limit
-	// access to the entire disk regardless of partitions
-	value := fmt.Sprintf("%v:0 rbps=%d wbps=%d", major, limit, limit)
-
-	return writeToFilename(filepath.Join(c.path, "io.max"), value)
-}
-
 func writeToFilename(path, value string) error {
 	if err := os.WriteFile(path, []byte(value), cgroupFilePerm); err != nil {
 		return fmt.Errorf("could not write to %s: %w", path, err)
@@ -164,29 +289,3 @@ func writeToFilename(path, value string) error {
 
 	return nil
 }
-
-func getDeviceForMount(mountpoint string) (string, error) {
-	file, err := os.Open(procMountsPath)
-	if err != nil {
-		return "", fmt.Errorf("failed opening file: %w", err)
-	}
-	defer file.Close()
-
-	// A proc mounts file takes the following format:
-	// <device> <mount> <fstype> <fsoptions> <dump> <passno>
-	// An example for the file looks like:
-	// /dev/nvme0n1p4 / btrfs rw,seclabel,relatime,compress=zstd:1,ssd,discard=async,space_cache=v2,subvolid=256,subvol=/root 0 0
-	// We need to return the device for given mountpoint
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		fields := strings.Fields(scanner.Text())
-		if len(fields) != numProcMountFields {
-			continue
-		}
-		if fields[1] == mountpoint {
-			return fields[0], nil
-		}
-	}
-
-	return "", fmt.Errorf("mountpoint %s not found", mountpoint)
-}
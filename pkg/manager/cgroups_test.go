@@ -4,8 +4,10 @@ import (
 	"jobworker/pkg/manager"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
 func assertLineContent(t *testing.T, filePath, expectedRegex string) {
@@ -41,10 +43,10 @@ func TestCgroups(t *testing.T) {
 		t.Fatalf("Failed creating cgroup %v: %v", cgrp, err)
 	}
 
-	assertLineContent(t, filepath.Join(tmpdir, "cgroup.subtree_control"), `^\+cpu \+memory \+io$`)
+	assertLineContent(t, filepath.Join(tmpdir, "cgroup.subtree_control"), `^\+cpu \+memory \+io \+pids$`)
 	assertLineContent(t, filepath.Join(tmpdir, "gizmo", "cpu.max"), "^100 1000000$")
 	assertLineContent(t, filepath.Join(tmpdir, "gizmo", "memory.max"), "^200$")
-	assertLineContent(t, filepath.Join(tmpdir, "gizmo", "io.max"), `^\d+:\d+ rbps=300 wbps=300$`)
+	assertLineContent(t, filepath.Join(tmpdir, "gizmo", "io.max"), `^\d+:\d+ rbps=300 wbps=300 riops=max wiops=max\n$`)
 
 	if err := cgrp.Delete(); err != nil {
 		t.Fatalf("Failed delting cgroup: %v", err)
@@ -54,4 +56,77 @@ func TestCgroups(t *testing.T) {
 	if !os.IsNotExist(err) {
 		t.Fatalf("Cgroup was not deleted")
 	}
+
+	// A device limit replaces the default limit of the same disk, the rates
+	// it leaves unset keep the default rather than being lifted
+	limits.IOLimits = []manager.IOLimit{{Device: "/", WriteBytesPerSec: 400, ReadIOPS: 10}}
+	if err := cgrp.Create(limits); err != nil {
+		t.Fatalf("Failed creating cgroup %v: %v", cgrp, err)
+	}
+	defer cgrp.Delete()
+
+	assertLineContent(t, filepath.Join(tmpdir, "gizmo", "io.max"), `^\d+:\d+ rbps=300 wbps=400 riops=10 wiops=max\n$`)
+
+	limits.IOLimits = []manager.IOLimit{{Device: "/dev/null", ReadIOPS: 10}}
+	if err := limits.Validate(); err == nil {
+		t.Fatalf("expected a limit of a character device to be invalid")
+	}
+}
+
+func TestCgroupUsage(t *testing.T) {
+	t.Parallel()
+
+	tmpdir := t.TempDir()
+	cgrp := manager.NewCgroup(tmpdir, "gizmo")
+	if err := cgrp.Create(&manager.ResourceLimits{}); err != nil {
+		t.Fatalf("Failed creating cgroup %v: %v", cgrp, err)
+	}
+
+	// memory.peak is missing on older kernels
+	files := map[string]string{
+		"cpu.stat":       "usage_usec 3000\nuser_usec 2000\nsystem_usec 1000\nnr_periods 10\nnr_throttled 4\nthrottled_usec 500\n",
+		"memory.current": "4096\n",
+		"memory.events":  "low 0\nhigh 1\nmax 2\noom 3\noom_kill 1\n",
+		"io.stat":        "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0\n253:1 rbytes=10 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n",
+		"pids.current":   "3\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpdir, "gizmo", name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed writing %s: %v", name, err)
+		}
+	}
+
+	usage, err := cgrp.Usage()
+	if err != nil {
+		t.Fatalf("Failed reading usage: %v", err)
+	}
+
+	if usage.CPUUsage != 3*time.Millisecond || usage.CPUUser != 2*time.Millisecond ||
+		usage.CPUSystem != time.Millisecond || usage.CPUThrottled != 500*time.Microsecond || usage.ThrottledPeriods != 4 {
+		t.Fatalf("wrong cpu usage %+v", usage)
+	}
+
+	if usage.MemoryCurrent != 4096 || usage.MemoryPeak != 0 || usage.PidsCurrent != 3 {
+		t.Fatalf("wrong memory or pids usage %+v", usage)
+	}
+
+	expectedEvents := manager.MemoryEvents{High: 1, Max: 2, OOM: 3, OOMKill: 1}
+	if usage.MemoryEvents != expectedEvents {
+		t.Fatalf("wrong memory events %+v", usage.MemoryEvents)
+	}
+
+	expectedIO := []manager.DeviceIO{
+		{Major: 8, Minor: 0, ReadBytes: 1024, WriteBytes: 2048, ReadIOs: 1, WriteIOs: 2},
+		{Major: 253, Minor: 1, ReadBytes: 10, ReadIOs: 1},
+	}
+	if !reflect.DeepEqual(usage.IO, expectedIO) {
+		t.Fatalf("wrong io usage %+v", usage.IO)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpdir, "gizmo", "memory.current"), []byte("lots\n"), 0o644); err != nil {
+		t.Fatalf("Failed writing memory.current: %v", err)
+	}
+	if _, err := cgrp.Usage(); err == nil {
+		t.Fatalf("expected invalid usage to fail")
+	}
 }
@@ -0,0 +1,229 @@
+package manager
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"syscall"
+)
+
+// compressedLogSuffix is appended to the path of a compressed log segment
+const compressedLogSuffix = ".gz"
+
+// LogCompression is the algorithm the logs of stopped jobs are compressed
+// with, only gzip is supported since the standard library has no zstd
+// encoder
+type LogCompression int32
+
+const (
+	CompressionNone LogCompression = iota
+	CompressionGzip
+)
+
+func (c LogCompression) String() string {
+	return [...]string{"None", "Gzip"}[c]
+}
+
+// WithLogCompression compresses the log of every job once it stopped, in
+// the background.  Compressed logs are streamed like any other log.
+func WithLogCompression(compression LogCompression) ManagerOption {
+	return func(m *JobManager) {
+		m.compression = compression
+	}
+}
+
+// LogCompression returns the algorithm the job's log was compressed with,
+// CompressionNone until it is compressed
+func (j *JobInfo) LogCompression() LogCompression {
+	return LogCompression(j.logCompression.Load())
+}
+
+// LogSize returns the size of the job's log before it was compressed, it is
+// known once the job stopped
+func (j *JobInfo) LogSize() int64 {
+	return j.logBytes.Load()
+}
+
+// StoredLogSize returns the size the job's log takes on disk, it is known
+// once the job stopped
+func (j *JobInfo) StoredLogSize() int64 {
+	return j.storedLogBytes.Load()
+}
+
+// CompressionRatio returns the size of the log divided by its stored size,
+// or 0 if the size isn't known
+func (j *JobInfo) CompressionRatio() float64 {
+	stored := j.StoredLogSize()
+	if stored == 0 {
+		return 0
+	}
+	return float64(j.LogSize()) / float64(stored)
+}
+
+// logFiles returns the paths of all of the files that may hold the log,
+// compressed or not
+func logFiles(logPath string) []string {
+	var paths []string
+	for _, path := range logSegments(logPath) {
+		paths = append(paths, path, path+compressedLogSuffix, path+compressedLogSuffix+".tmp")
+	}
+	return paths
+}
+
+// openLogSegment opens the segment at path, or its compressed copy if it
+// was compressed.  It returns nil if neither exists.
+func openLogSegment(path string) (*logSegment, error) {
+	file, err := os.Open(path)
+	if err == nil {
+		return &logSegment{file: file}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed opening log file %s: %w", path, err)
+	}
+
+	// The segment is removed only once its compressed copy is complete
+	file, err = os.Open(path + compressedLogSuffix)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed opening log file %s: %w", path+compressedLogSuffix, err)
+	}
+
+	return &logSegment{file: file, compressed: true}, nil
+}
+
+// logSegment is an opened segment of a log, which may be compressed
+type logSegment struct {
+	file       *os.File
+	compressed bool
+}
+
+// reader returns a reader of the segment's content from its start
+func (s *logSegment) reader() (io.Reader, error) {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed rewinding log: %w", err)
+	}
+
+	if !s.compressed {
+		return s.file, nil
+	}
+
+	reader, err := gzip.NewReader(s.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed decompressing log: %w", err)
+	}
+
+	return reader, nil
+}
+
+// recordLogSize records the size of the log once the job stopped
+func (j *Job) recordLogSize() {
+	size := j.logSize()
+	j.logBytes.Store(size)
+	j.storedLogBytes.Store(size)
+}
+
+// compressLog:
+//   - Runs in a goroutine once the job stopped.
+//   - Compresses each of the log's segments, a segment is removed once its
+//     compressed copy is complete.  Streams that already opened it keep on
+//     reading it.
+//   - Holds logMu, so the log isn't deleted while it is compressed.
+func (j *Job) compressLog(compression LogCompression) {
+	j.logMu.Lock()
+	defer j.logMu.Unlock()
+
+	if j.logPath == "" || j.LogCompression() != CompressionNone {
+		return
+	}
+
+	var size, stored int64
+	compressed := 0
+
+	for _, path := range logSegments(j.logPath) {
+		segmentSize, segmentStored, err := compressLogSegment(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed compressing log of job %s: %v", j.jobID, err)
+			return
+		}
+
+		size += segmentSize
+		stored += segmentStored
+		compressed++
+	}
+
+	// The log was deleted
+	if compressed == 0 {
+		return
+	}
+
+	j.logBytes.Store(size)
+	j.storedLogBytes.Store(stored)
+	j.logCompression.Store(int32(compression))
+	j.persist()
+
+	log.Printf("Compressed log of job %s from %d to %d bytes", j.jobID, size, stored)
+}
+
+// compressLogSegment:
+//   - Compresses the segment at path next to it, with the same owner and
+//     permissions.
+//   - Returns the size of the segment and of its compressed copy.
+func compressLogSegment(path string) (int64, int64, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed reading log %s: %w", path, err)
+	}
+
+	tmpPath := path + compressedLogSuffix + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed creating compressed log %s: %w", tmpPath, err)
+	}
+	defer os.Remove(tmpPath)
+	defer dst.Close()
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if err := dst.Chown(int(stat.Uid), int(stat.Gid)); err != nil {
+			return 0, 0, fmt.Errorf("failed changing owner of compressed log %s: %w", tmpPath, err)
+		}
+	}
+
+	writer := gzip.NewWriter(dst)
+	if _, err := io.Copy(writer, src); err != nil {
+		return 0, 0, fmt.Errorf("failed compressing log %s: %w", path, err)
+	}
+	if err := writer.Close(); err != nil {
+		return 0, 0, fmt.Errorf("failed compressing log %s: %w", path, err)
+	}
+	if err := dst.Sync(); err != nil {
+		return 0, 0, fmt.Errorf("failed writing compressed log %s: %w", tmpPath, err)
+	}
+
+	compressedInfo, err := dst.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed reading compressed log %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path+compressedLogSuffix); err != nil {
+		return 0, 0, fmt.Errorf("failed renaming compressed log %s: %w", tmpPath, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return 0, 0, fmt.Errorf("failed removing log %s: %w", path, err)
+	}
+
+	return info.Size(), compressedInfo.Size(), nil
+}
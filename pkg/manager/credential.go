@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// Credential is the unix identity a job is executed with
+type Credential struct {
+	UID    uint32
+	GID    uint32
+	Groups []uint32
+}
+
+// WithCredential runs the job as the given uid/gid instead of the
+// server's own identity.
+func WithCredential(cred *Credential) JobOption {
+	return func(c *Job) {
+		c.credential = cred
+	}
+}
+
+// sysProcCredential converts the credential to the one used by exec.
+// Supplementary groups can only be set by a privileged server, an
+// unprivileged one keeps its own groups.
+func (c *Credential) sysProcCredential() *syscall.Credential {
+	return &syscall.Credential{
+		Uid:         c.UID,
+		Gid:         c.GID,
+		Groups:      c.Groups,
+		NoSetGroups: os.Geteuid() != 0,
+	}
+}
+
+// userEnv returns the USER, LOGNAME and HOME variables of the credential's
+// user, if it can be found in the user database.
+func (c *Credential) userEnv() []string {
+	u, err := user.LookupId(strconv.FormatUint(uint64(c.UID), 10))
+	if err != nil {
+		return nil
+	}
+
+	return []string{"USER=" + u.Username, "LOGNAME=" + u.Username, "HOME=" + u.HomeDir}
+}
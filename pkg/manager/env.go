@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultJobEnv is the minimal environment a job gets unless it asks to
+// inherit the server's environment.
+var defaultJobEnv = []string{
+	"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+	"HOME=/",
+	"LANG=C.UTF-8",
+}
+
+// validateEnv makes sure every entry looks like KEY=VALUE
+func validateEnv(env []string) error {
+	for _, entry := range env {
+		key, _, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid environment variable %q, expected KEY=VALUE", entry)
+		}
+		if strings.ContainsRune(entry, 0) {
+			return fmt.Errorf("environment variable %s contains a NUL byte", key)
+		}
+	}
+
+	return nil
+}
+
+// jobEnv:
+// - Starts from either the server's environment or the default one.
+// - Sets USER and HOME of the user the job runs as, if any.
+// - Appends the job's own variables, exec.Cmd keeps the last value of a
+// duplicate key so these take precedence.
+func (j *Job) jobEnv() []string {
+	var env []string
+	if j.inheritEnv {
+		env = append(env, os.Environ()...)
+	} else {
+		env = append(env, defaultJobEnv...)
+		if j.credential != nil {
+			env = append(env, j.credential.userEnv()...)
+		}
+	}
+
+	return append(env, j.env...)
+}
+
+// lookPath resolves the command against the PATH of the job's environment
+// rather than the server's, commands that contain a slash are returned as is.
+func lookPath(command string, env []string) (string, error) {
+	if strings.Contains(command, "/") {
+		return command, nil
+	}
+
+	path := ""
+	for _, entry := range env {
+		if value, ok := strings.CutPrefix(entry, "PATH="); ok {
+			path = value
+		}
+	}
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			dir = "."
+		}
+		candidate := filepath.Join(dir, command)
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("executable %s not found in $PATH", command)
+}
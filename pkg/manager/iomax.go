@@ -0,0 +1,365 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// IOLimit limits the io of a single block device, a zero value means that
+// the limit is not set
+type IOLimit struct {
+	// Device is a path on the device's filesystem (e.g a mount point), the
+	// device's node (e.g /dev/sdb) or its major:minor (e.g 8:16)
+	Device           string
+	ReadBytesPerSec  int64
+	WriteBytesPerSec int64
+	ReadIOPS         int64
+	WriteIOPS        int64
+}
+
+// validate makes sure the limit sets a non negative value for a device
+func (l IOLimit) validate() error {
+	if l.Device == "" {
+		return fmt.Errorf("io limit is missing its device")
+	}
+
+	if l.ReadBytesPerSec < 0 || l.WriteBytesPerSec < 0 || l.ReadIOPS < 0 || l.WriteIOPS < 0 {
+		return fmt.Errorf("io limit for %s cannot be negative", l.Device)
+	}
+
+	if l.ReadBytesPerSec == 0 && l.WriteBytesPerSec == 0 && l.ReadIOPS == 0 && l.WriteIOPS == 0 {
+		return fmt.Errorf("io limit for %s does not limit anything", l.Device)
+	}
+
+	return nil
+}
+
+// ioMaxValue returns the io.max line of the limit, unset limits are "max"
+func (l IOLimit) ioMaxValue(device blockDevice) string {
+	value := func(limit int64) string {
+		if limit == 0 {
+			return "max"
+		}
+		return strconv.FormatInt(limit, 10)
+	}
+
+	return fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s", device,
+		value(l.ReadBytesPerSec), value(l.WriteBytesPerSec), value(l.ReadIOPS), value(l.WriteIOPS))
+}
+
+// ioMaxEntry is an io limit along with the disk it applies to
+type ioMaxEntry struct {
+	device blockDevice
+	limit  IOLimit
+}
+
+// resolveIOLimits resolves the disk of every io limit, two limits cannot
+// apply to the same disk
+func (l ResourceLimits) resolveIOLimits(devices *blockDevices) ([]ioMaxEntry, error) {
+	var entries []ioMaxEntry
+	limited := make(map[blockDevice]string)
+
+	for _, limit := range l.IOLimits {
+		if err := limit.validate(); err != nil {
+			return nil, err
+		}
+
+		device, err := devices.resolve(limit.Device)
+		if err != nil {
+			return nil, fmt.Errorf("invalid io limit for %s: %w", limit.Device, err)
+		}
+
+		if other, ok := limited[device]; ok {
+			return nil, fmt.Errorf("io limits for %s and %s both apply to device %s", other, limit.Device, device)
+		}
+		limited[device] = limit.Device
+
+		entries = append(entries, ioMaxEntry{device: device, limit: limit})
+	}
+
+	return entries, nil
+}
+
+// ioMaxEntries:
+//   - Resolves the disk of every io limit.
+//   - IOMaxBytesPerSec limits reads and writes of the disk that backs
+//     `ioMaxMountPoint`.  An io limit of the same disk replaces it, but
+//     the rates it leaves unset keep IOMaxBytesPerSec rather than being
+//     unlimited.
+func (l ResourceLimits) ioMaxEntries(devices *blockDevices) ([]ioMaxEntry, error) {
+	entries, err := l.resolveIOLimits(devices)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.IOMaxBytesPerSec == 0 {
+		return entries, nil
+	}
+
+	device, err := devices.resolve(ioMaxMountPoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed resolving the device of %s: %w", ioMaxMountPoint, err)
+	}
+
+	for i := range entries {
+		if entries[i].device != device {
+			continue
+		}
+
+		limit := &entries[i].limit
+		if limit.ReadBytesPerSec == 0 {
+			limit.ReadBytesPerSec = l.IOMaxBytesPerSec
+		}
+		if limit.WriteBytesPerSec == 0 {
+			limit.WriteBytesPerSec = l.IOMaxBytesPerSec
+		}
+		return entries, nil
+	}
+
+	return append(entries, ioMaxEntry{
+		device: device,
+		limit: IOLimit{
+			Device:           ioMaxMountPoint,
+			ReadBytesPerSec:  l.IOMaxBytesPerSec,
+			WriteBytesPerSec: l.IOMaxBytesPerSec,
+		},
+	}), nil
+}
+
+// blockDevice is the major:minor of a block device
+type blockDevice struct {
+	major uint32
+	minor uint32
+}
+
+func (d blockDevice) String() string {
+	return fmt.Sprintf("%d:%d", d.major, d.minor)
+}
+
+// parseBlockDevice parses a major:minor such as 8:16
+func parseBlockDevice(value string) (blockDevice, bool) {
+	majorValue, minorValue, found := strings.Cut(strings.TrimSpace(value), ":")
+	if !found {
+		return blockDevice{}, false
+	}
+
+	major, err := strconv.ParseUint(majorValue, 10, 32)
+	if err != nil {
+		return blockDevice{}, false
+	}
+
+	minor, err := strconv.ParseUint(minorValue, 10, 32)
+	if err != nil {
+		return blockDevice{}, false
+	}
+
+	return blockDevice{major: uint32(major), minor: uint32(minor)}, true
+}
+
+// blockDevices finds the disks io limits apply to, its paths are only
+// replaced in tests
+type blockDevices struct {
+	// sysDevBlock holds a link to the sysfs directory of every block
+	// device, named by its major:minor
+	sysDevBlock string
+	mountInfo   string
+}
+
+var hostBlockDevices = &blockDevices{
+	sysDevBlock: "/sys/dev/block",
+	mountInfo:   "/proc/self/mountinfo",
+}
+
+// resolve:
+//   - Finds the block device of a major:minor, a device node or a path on
+//     a filesystem.  Filesystems that don't report a block device of their
+//     own, e.g btrfs, are resolved through the source of their mount.
+//   - Resolves a partition to its disk, since io.max only limits whole
+//     disks.  Device-mapper devices are limited as they are.
+//   - Fails for anything that isn't a known block device.
+func (d *blockDevices) resolve(target string) (blockDevice, error) {
+	device, ok := parseBlockDevice(target)
+	if !ok {
+		var err error
+		if device, err = d.deviceOfPath(target); err != nil {
+			return blockDevice{}, err
+		}
+	}
+
+	sysPath, err := filepath.EvalSymlinks(filepath.Join(d.sysDevBlock, device.String()))
+	if os.IsNotExist(err) {
+		return blockDevice{}, fmt.Errorf("%s is not a known block device", device)
+	}
+	if err != nil {
+		return blockDevice{}, fmt.Errorf("failed finding block device %s: %w", device, err)
+	}
+
+	// Partitions are listed in the directory of their disk
+	if _, err := os.Stat(filepath.Join(sysPath, "partition")); err != nil {
+		return device, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(sysPath), "dev"))
+	if err != nil {
+		return blockDevice{}, fmt.Errorf("failed finding the disk of partition %s: %w", device, err)
+	}
+
+	disk, ok := parseBlockDevice(string(data))
+	if !ok {
+		return blockDevice{}, fmt.Errorf("invalid disk %q of partition %s", data, device)
+	}
+
+	log.Printf("Resolved partition %s of %s to disk %s", device, target, disk)
+
+	return disk, nil
+}
+
+// deviceOfPath returns the device of a device node, or the device the
+// filesystem of path is on
+func (d *blockDevices) deviceOfPath(path string) (blockDevice, error) {
+	if !filepath.IsAbs(path) {
+		return blockDevice{}, fmt.Errorf("device %q must be an absolute path or a major:minor", path)
+	}
+
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return blockDevice{}, fmt.Errorf("error calling stat on %s: %w", path, err)
+	}
+
+	switch stat.Mode & unix.S_IFMT {
+	case unix.S_IFBLK:
+		return blockDevice{major: unix.Major(stat.Rdev), minor: unix.Minor(stat.Rdev)}, nil
+	case unix.S_IFCHR:
+		return blockDevice{}, fmt.Errorf("%s is a character device, not a block device", path)
+	}
+
+	device := blockDevice{major: unix.Major(stat.Dev), minor: unix.Minor(stat.Dev)}
+	if device.major != 0 {
+		return device, nil
+	}
+
+	// Major 0 is used by filesystems without a device of their own
+	source, fsType, err := d.mountSource(path)
+	if err != nil {
+		return blockDevice{}, err
+	}
+
+	if err := unix.Stat(source, &stat); err != nil || stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return blockDevice{}, fmt.Errorf("%s is on a %s filesystem, which is not backed by a block device", path, fsType)
+	}
+
+	return blockDevice{major: unix.Major(stat.Rdev), minor: unix.Minor(stat.Rdev)}, nil
+}
+
+// mountSource returns the source and the type of the filesystem mounted
+// at path, or at the closest directory above it
+func (d *blockDevices) mountSource(path string) (string, string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed resolving %s: %w", path, err)
+	}
+
+	file, err := os.Open(d.mountInfo)
+	if err != nil {
+		return "", "", fmt.Errorf("failed opening file: %w", err)
+	}
+	defer file.Close()
+
+	// A mountinfo line takes the following format, the optional fields
+	// end with a "-":
+	// <id> <parent> <major:minor> <root> <mount> <options> [optional...] - <fstype> <source> <superoptions>
+	// For example:
+	// 28 1 0:31 /root / rw,relatime shared:1 - btrfs /dev/nvme0n1p4 rw,compress=zstd:1,ssd,subvol=/root
+	// The last mount that covers path is the one it is on
+	var source, fsType, mountPoint string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		separator := -1
+		for i, field := range fields {
+			if field == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator < 5 || len(fields) < separator+3 {
+			continue
+		}
+
+		mount := unescapeMountField(fields[4])
+		if !isPathUnder(resolved, mount) || len(mount) < len(mountPoint) {
+			continue
+		}
+
+		mountPoint = mount
+		fsType = fields[separator+1]
+		source = unescapeMountField(fields[separator+2])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("failed reading %s: %w", d.mountInfo, err)
+	}
+
+	if mountPoint == "" {
+		return "", "", fmt.Errorf("mount of %s not found", path)
+	}
+
+	return source, fsType, nil
+}
+
+// isPathUnder returns true if path is dir or is in it
+func isPathUnder(path, dir string) bool {
+	return dir == "/" || path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// unescapeMountField decodes the octal escapes mountinfo uses for spaces,
+// tabs, newlines and backslashes, e.g "\040"
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+
+	var unescaped strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+4 <= len(field) {
+			if value, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				unescaped.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+		unescaped.WriteByte(field[i])
+	}
+
+	return unescaped.String()
+}
+
+// setIOLimits writes a line to io.max for every disk that is limited, the
+// kernel parses a single device per write
+func (c *Cgroup) setIOLimits(entries []ioMaxEntry) error {
+	path := filepath.Join(c.path, "io.max")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, cgroupFilePerm)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	for _, entry := range entries {
+		value := entry.limit.ioMaxValue(entry.device)
+
+		log.Printf("Limiting io of device %s (%s): %s", entry.device, entry.limit.Device, value)
+
+		if _, err := file.WriteString(value + "\n"); err != nil {
+			return fmt.Errorf("could not write %q to %s: %w", value, path, err)
+		}
+	}
+
+	return nil
+}
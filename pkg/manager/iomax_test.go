@@ -0,0 +1,154 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestBlockDevices creates a fake /sys/dev/block with a disk, one of its
+// partitions and a device-mapper device
+func newTestBlockDevices(t *testing.T) *blockDevices {
+	t.Helper()
+
+	root := t.TempDir()
+	devices := map[string]string{
+		"devices/sda":      "8:0",
+		"devices/sda/sda1": "8:1",
+		"devices/dm-0":     "253:0",
+	}
+	for dir, device := range devices {
+		path := filepath.Join(root, dir)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("Failed creating %s: %v", path, err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "dev"), []byte(device+"\n"), 0o644); err != nil {
+			t.Fatalf("Failed writing dev of %s: %v", dir, err)
+		}
+		if err := os.Symlink(path, filepath.Join(root, device)); err != nil {
+			t.Fatalf("Failed linking %s: %v", device, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "devices/sda/sda1/partition"), []byte("1\n"), 0o644); err != nil {
+		t.Fatalf("Failed writing partition: %v", err)
+	}
+
+	mountInfo := filepath.Join(root, "mountinfo")
+	content := "22 1 0:31 /root / rw,relatime shared:1 - btrfs /dev/sda1 rw,subvol=/root\n" +
+		"23 22 0:32 / /mnt/my\\040data rw,relatime - btrfs /dev/dm-0 rw\n" +
+		"24 22 0:5 / /dev rw,nosuid shared:2 - devtmpfs devtmpfs rw\n"
+	if err := os.WriteFile(mountInfo, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed writing mountinfo: %v", err)
+	}
+
+	return &blockDevices{sysDevBlock: root, mountInfo: mountInfo}
+}
+
+func TestResolveBlockDevice(t *testing.T) {
+	t.Parallel()
+
+	devices := newTestBlockDevices(t)
+
+	// Partitions are resolved to their disk, device-mapper devices are kept
+	tests := map[string]blockDevice{
+		"8:0":   {major: 8},
+		"8:1":   {major: 8},
+		"253:0": {major: 253},
+	}
+	for target, expected := range tests {
+		device, err := devices.resolve(target)
+		if err != nil {
+			t.Fatalf("resolve(%q) failed: %v", target, err)
+		}
+		if device != expected {
+			t.Fatalf("resolve(%q) = %s, expected %s", target, device, expected)
+		}
+	}
+
+	// proc is looked up in the host's mounts
+	hostDevices := &blockDevices{sysDevBlock: devices.sysDevBlock, mountInfo: hostBlockDevices.mountInfo}
+	failures := map[string]string{
+		"8:16":         "not a known block device",
+		"data":         "must be an absolute path",
+		"/dev/null":    "character device",
+		"/nonexistent": "no such file",
+		// proc has no device of its own, and isn't mounted from one
+		"/proc": "not backed by a block device",
+	}
+	for target, expected := range failures {
+		if _, err := hostDevices.resolve(target); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("resolve(%q) should have failed with %q, received %v", target, expected, err)
+		}
+	}
+}
+
+func TestMountSource(t *testing.T) {
+	t.Parallel()
+
+	devices := newTestBlockDevices(t)
+
+	// The closest mount above the path is used
+	tests := map[string]string{
+		"/":    "/dev/sda1",
+		"/tmp": "/dev/sda1",
+		"/dev": "devtmpfs",
+	}
+	for path, expected := range tests {
+		source, _, err := devices.mountSource(path)
+		if err != nil {
+			t.Fatalf("mountSource(%q) failed: %v", path, err)
+		}
+		if source != expected {
+			t.Fatalf("mountSource(%q) = %s, expected %s", path, source, expected)
+		}
+	}
+
+	if unescaped := unescapeMountField(`/mnt/my\040data`); unescaped != "/mnt/my data" {
+		t.Fatalf("wrong unescaped mount point %q", unescaped)
+	}
+}
+
+func TestIOMaxEntries(t *testing.T) {
+	t.Parallel()
+
+	devices := newTestBlockDevices(t)
+
+	limits := ResourceLimits{IOLimits: []IOLimit{
+		{Device: "8:1", ReadBytesPerSec: 1 << 20, WriteIOPS: 100},
+		{Device: "253:0", WriteBytesPerSec: 1 << 10},
+	}}
+	entries, err := limits.ioMaxEntries(devices)
+	if err != nil {
+		t.Fatalf("Failed resolving io limits: %v", err)
+	}
+
+	cgroup := NewCgroup(t.TempDir(), "")
+	if err := cgroup.setIOLimits(entries); err != nil {
+		t.Fatalf("Failed setting io limits: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cgroup.path, "io.max"))
+	if err != nil {
+		t.Fatalf("Failed reading io.max: %v", err)
+	}
+
+	expected := "8:0 rbps=1048576 wbps=max riops=max wiops=100\n253:0 rbps=max wbps=1024 riops=max wiops=max\n"
+	if string(data) != expected {
+		t.Fatalf("wrong io.max, expected=[%s], received=[%s]", expected, data)
+	}
+
+	invalid := [][]IOLimit{
+		// Both apply to the same disk
+		{{Device: "8:0", ReadIOPS: 1}, {Device: "8:1", WriteIOPS: 1}},
+		{{Device: "8:0"}},
+		{{Device: "8:0", ReadIOPS: -1}},
+		{{ReadIOPS: 1}},
+	}
+	for _, ioLimits := range invalid {
+		if _, err := (ResourceLimits{IOLimits: ioLimits}).ioMaxEntries(devices); err == nil {
+			t.Fatalf("expected io limits %+v to be rejected", ioLimits)
+		}
+	}
+}
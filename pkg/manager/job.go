@@ -7,7 +7,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/sys/unix"
@@ -17,9 +19,9 @@ const (
 	cgroupSysFsRoot              = "/sys/fs/cgroup"
 	jobWorkerManagerLogDir       = "/tmp/jobworker"
 	jobWorkerLogDirPerms         = 0o755
-	jobWorkerCPUMaxQuotaMicroSec = 500_000
-	jobWorkerMemMaxBytes         = 500_000
-	jobWorkerIoMaxBps            = 500_000
+	jobWorkerCPUMaxQuotaMicroSec = 1_000_000
+	jobWorkerMemMaxBytes         = 1 << 30
+	jobWorkerIoMaxBps            = 100 << 20
 )
 
 type JobStatus int32
@@ -36,9 +38,67 @@ func (s JobStatus) String() string {
 	return [...]string{"Init", "Scheduled", "FailedToStart", "Running", "Stopped"}[s]
 }
 
-// These JobOptions are used for testing only.
 type JobOption func(*Job)
 
+// WithResourceLimits overrides the default cgroup limits for the job,
+// fields that are left as zero keep their default value.
+func WithResourceLimits(limits ResourceLimits) JobOption {
+	return func(c *Job) {
+		if limits.CPUMaxQuotaMicroSec > 0 {
+			c.limits.CPUMaxQuotaMicroSec = limits.CPUMaxQuotaMicroSec
+		}
+		if limits.MemMaxBytes > 0 {
+			c.limits.MemMaxBytes = limits.MemMaxBytes
+		}
+		if limits.IOMaxBytesPerSec > 0 {
+			c.limits.IOMaxBytesPerSec = limits.IOMaxBytesPerSec
+		}
+		if len(limits.IOLimits) > 0 {
+			c.limits.IOLimits = append([]IOLimit(nil), limits.IOLimits...)
+		}
+	}
+}
+
+// WithOwner records the identity that requested the job.
+func WithOwner(owner string) JobOption {
+	return func(c *Job) {
+		c.owner = owner
+	}
+}
+
+// WithLabels attaches user defined key/value labels to the job.
+func WithLabels(labels map[string]string) JobOption {
+	return func(c *Job) {
+		c.labels = make(map[string]string, len(labels))
+		for k, v := range labels {
+			c.labels[k] = v
+		}
+	}
+}
+
+// WithEnv sets additional KEY=VALUE environment variables for the job.
+func WithEnv(env []string) JobOption {
+	return func(c *Job) {
+		c.env = append([]string(nil), env...)
+	}
+}
+
+// WithWorkingDir sets the directory the job is executed in.
+func WithWorkingDir(dir string) JobOption {
+	return func(c *Job) {
+		c.workDir = dir
+	}
+}
+
+// WithInheritEnv makes the job inherit the server's environment instead
+// of the minimal default one.
+func WithInheritEnv(inherit bool) JobOption {
+	return func(c *Job) {
+		c.inheritEnv = inherit
+	}
+}
+
+// These JobOptions are used for testing only.
 func WithCloneFlags(flags uintptr) JobOption {
 	return func(c *Job) {
 		c.cloneFlags = flags
@@ -51,13 +111,53 @@ func WithCgroup(cgroup *Cgroup) JobOption {
 	}
 }
 
+// withCgroupRoot creates the job's cgroup under root
+func withCgroupRoot(root string) JobOption {
+	return func(c *Job) {
+		c.cgroup = NewCgroup(root, c.jobID)
+	}
+}
+
 type JobInfo struct {
-	jobID    string
-	pid      atomic.Int32
-	exitCode atomic.Int32
-	command  string
-	args     []string
-	status   atomic.Int32
+	jobID     string
+	pid       atomic.Int32
+	exitCode  atomic.Int32
+	command   string
+	args      []string
+	status    atomic.Int32
+	limits    ResourceLimits
+	owner     string
+	labels    map[string]string
+	createdAt time.Time
+	tty       bool
+	// startedAt and finishedAt hold times in unix nanoseconds, 0 if the job
+	// did not reach them
+	startedAt  atomic.Int64
+	finishedAt atomic.Int64
+	// executable holds the resolved path of command
+	executable atomic.Value
+	// procStartTime tells the job's process apart from a later process
+	// with the same pid
+	procStartTime uint64
+	// deadline holds the time in unix nanoseconds the job is stopped at,
+	// 0 if it has no timeout
+	deadline   atomic.Int64
+	timedOut   atomic.Bool
+	reason     atomic.Int32
+	termSignal atomic.Int32
+	// errorMessage holds the reason the job failed to start
+	errorMessage atomic.Value
+	logLimit     int64
+	logPolicy    LogPolicy
+	logTruncated atomic.Bool
+	// logBytes and storedLogBytes hold the size of the log before and
+	// after it was compressed, once the job stopped
+	logBytes       atomic.Int64
+	storedLogBytes atomic.Int64
+	logCompression atomic.Int32
+	// usage holds the latest resource usage of the job, which is final once
+	// it stopped
+	usage atomic.Pointer[ResourceUsage]
 }
 
 func (j *JobInfo) JobID() string {
@@ -76,10 +176,126 @@ func (j *JobInfo) ProcessID() int32 {
 	return j.pid.Load()
 }
 
+// Limits returns the resource limits that are applied to the job's cgroup
+func (j *JobInfo) Limits() ResourceLimits {
+	return j.limits
+}
+
+func (j *JobInfo) Command() string {
+	return j.command
+}
+
+// Executable returns the path command was resolved to, or an empty string
+// if the job did not get that far
+func (j *JobInfo) Executable() string {
+	if path, ok := j.executable.Load().(string); ok {
+		return path
+	}
+	return ""
+}
+
+// Args returns a copy of the command's arguments
+func (j *JobInfo) Args() []string {
+	return append([]string(nil), j.args...)
+}
+
+func (j *JobInfo) Owner() string {
+	return j.owner
+}
+
+// Labels returns a copy of the job's labels
+func (j *JobInfo) Labels() map[string]string {
+	labels := make(map[string]string, len(j.labels))
+	for k, v := range j.labels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// TTY returns true if the job runs with a pseudo terminal
+func (j *JobInfo) TTY() bool {
+	return j.tty
+}
+
+func (j *JobInfo) CreatedAt() time.Time {
+	return j.createdAt
+}
+
+// StartedAt returns the time the process was started, or a zero time if
+// the job never started.
+func (j *JobInfo) StartedAt() time.Time {
+	if nsec := j.startedAt.Load(); nsec != 0 {
+		return time.Unix(0, nsec)
+	}
+	return time.Time{}
+}
+
+// FinishedAt returns the time the job's process exited or it failed to
+// start, or a zero time if it is still running.
+func (j *JobInfo) FinishedAt() time.Time {
+	if nsec := j.finishedAt.Load(); nsec != 0 {
+		return time.Unix(0, nsec)
+	}
+	return time.Time{}
+}
+
+// Duration returns how long the job has been running, or ran for if it
+// already finished.
+func (j *JobInfo) Duration() time.Duration {
+	startedAt := j.StartedAt()
+	if startedAt.IsZero() {
+		return 0
+	}
+
+	finishedAt := j.FinishedAt()
+	if finishedAt.IsZero() {
+		return time.Since(startedAt)
+	}
+	return finishedAt.Sub(startedAt)
+}
+
 type Job struct {
 	*JobInfo
-	logFile    *os.File
-	cancelFunc context.CancelFunc
+	logDir  string
+	logPath string
+	output  *logWriter
+	// outputCopier copies the job's stdout and stderr to its log
+	outputCopier *outputCopier
+	// logMu is held while the log is compressed or deleted
+	logMu sync.Mutex
+	// compression is what the log is compressed with once the job stopped
+	compression LogCompression
+	cancelFunc  context.CancelFunc
+	env         []string
+	workDir     string
+	inheritEnv  bool
+	credential  *Credential
+	openStdin   bool
+	// stdinPipe is the write end of the job's stdin, if it was requested
+	stdinPipe     *os.File
+	stdinAttached atomic.Bool
+	timeout       time.Duration
+	ttyRows       uint16
+	ttyCols       uint16
+	pty           *pty
+	// exited is closed once the main process exited
+	exited chan struct{}
+	// stopMu is held while a stop is requested and while the exit of the
+	// main process is recorded, so a stop either finds the process alive
+	// or counts as having happened after it exited
+	stopMu sync.Mutex
+	// processUsage is the rusage of the main process once it exited
+	processUsage *ProcessUsage
+	// stopDeadline holds the time in unix nanoseconds after which a stopped
+	// job is killed, 0 if it wasn't stopped
+	stopDeadline atomic.Int64
+	watchers     statusWatchers
+	// store persists the job on every status transition, if set
+	store JobStore
+	// storeMu is held while the job is saved to or deleted from the store,
+	// so a job that was deleted is never saved again
+	storeMu sync.Mutex
+	deleted bool
 	// cloneFlags and cgroup are modified in tests only
 	cloneFlags uintptr
 	cgroup     *Cgroup
@@ -90,10 +306,14 @@ func NewJob(command string, args []string, opts ...JobOption) (*Job, error) {
 
 	ret := &Job{
 		JobInfo: &JobInfo{
-			jobID:   jobID,
-			command: command,
-			args:    args,
+			jobID:     jobID,
+			command:   command,
+			args:      args,
+			limits:    DefaultResourceLimits(),
+			createdAt: time.Now(),
 		},
+		logDir:     jobWorkerManagerLogDir,
+		exited:     make(chan struct{}),
 		cloneFlags: unix.CLONE_NEWNS | unix.CLONE_NEWPID | unix.CLONE_NEWNET,
 		cgroup:     NewCgroup(cgroupSysFsRoot, jobID),
 	}
@@ -102,6 +322,22 @@ func NewJob(command string, args []string, opts ...JobOption) (*Job, error) {
 		opt(ret)
 	}
 
+	if err := validateEnv(ret.env); err != nil {
+		return nil, err
+	}
+
+	if ret.workDir != "" && !filepath.IsAbs(ret.workDir) {
+		return nil, fmt.Errorf("working directory %s must be an absolute path", ret.workDir)
+	}
+
+	if ret.timeout < 0 {
+		return nil, fmt.Errorf("timeout %v must not be negative", ret.timeout)
+	}
+
+	if ret.logLimit < 0 || ret.logPolicy < LogTruncate || ret.logPolicy > LogKill {
+		return nil, fmt.Errorf("invalid log limit %d with policy %d", ret.logLimit, ret.logPolicy)
+	}
+
 	return ret, nil
 }
 
@@ -113,30 +349,81 @@ func (j *Job) start(ctx context.Context) error {
 	if swapped := j.status.CompareAndSwap(int32(JobInit), int32(JobScheduled)); !swapped {
 		return fmt.Errorf("invalid initial status for %s", j.jobID)
 	}
+	j.statusChanged(JobScheduled)
 
 	if err := j.initCgroup(); err != nil {
-		j.stop(JobScheduled, JobFailedToStart)
-		return fmt.Errorf("failed initializing cgroup for job %s: %w", j.jobID, err)
+		return j.failStart(fmt.Errorf("failed initializing cgroup for job %s: %w", j.jobID, err))
 	}
 
-	// logFile will look like $jobWorkerManagerLogDir/$jobId.log
+	// logFile will look like $logDir/$jobId.log
 	if err := j.openLogFile(); err != nil {
-		j.stop(JobScheduled, JobFailedToStart)
-		return fmt.Errorf("failed opening logfile: %w", err)
+		return j.failStart(fmt.Errorf("failed opening logfile: %w", err))
+	}
+
+	// The command is resolved using the job's environment so it won't
+	// depend on the server's PATH
+	env := j.jobEnv()
+	path, err := lookPath(j.command, env)
+	if err != nil {
+		return j.failStart(fmt.Errorf("failed resolving command for %s: %w", j.jobID, err))
+	}
+	j.executable.Store(path)
+
+	// Execute and redirect stdout and stderr to the logfile, each through
+	// its own fifo so the log tells them apart.
+	// The job itself is not logged since its environment may hold secrets.
+	log.Printf("Executing job %s: %s %v cgrp=%v", j.jobID, path, j.args, j.cgroup)
+
+	stdin, err := j.openStdinPipe()
+	if err != nil {
+		return j.failStart(fmt.Errorf("failed preparing stdin for %s: %w", j.jobID, err))
 	}
 
-	// Execute and redirect stdout and std err to logfile.
-	log.Printf("Executing job: %v cgrp=%v", j, j.cgroup)
+	stdout, stderr, err := j.openOutput()
+	if err != nil {
+		if stdin != nil {
+			stdin.Close()
+		}
+		return j.failStart(fmt.Errorf("failed preparing output for %s: %w", j.jobID, err))
+	}
 
-	// Save the context cancelation function in job in case
-	// we would like to stop it
+	terminal, err := j.openTerminal()
+	if err != nil {
+		if stdin != nil {
+			stdin.Close()
+		}
+		if stdout != nil {
+			stdout.Close()
+			stderr.Close()
+		}
+		return j.failStart(fmt.Errorf("failed preparing terminal for %s: %w", j.jobID, err))
+	}
+
+	// Save the context cancelation function in job so the context
+	// can be released once the process exits
 	var cmdCtx context.Context
 	cmdCtx, j.cancelFunc = context.WithCancel(ctx)
 
 	// Prepare the command and its attributes
-	cmd := exec.CommandContext(cmdCtx, j.command, j.args...)
-	cmd.Stdout = j.logFile
-	cmd.Stderr = j.logFile
+	cmd := exec.CommandContext(cmdCtx, path, j.args...)
+	cmd.Args[0] = j.command
+	cmd.Env = env
+	cmd.Dir = j.workDir
+	if stdout != nil {
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+	}
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+
+	// A terminal replaces all of the standard streams, its output is copied
+	// to the logfile by the pty
+	if terminal != nil {
+		cmd.Stdin = terminal
+		cmd.Stdout = terminal
+		cmd.Stderr = terminal
+	}
 
 	// Execute the process in new namespaces if applicable
 	attrs := &unix.SysProcAttr{
@@ -144,6 +431,20 @@ func (j *Job) start(ctx context.Context) error {
 		Setpgid:    true,
 	}
 
+	// A terminal needs a new session that it is the controlling terminal of,
+	// setsid also puts the process in a new process group.
+	if terminal != nil {
+		attrs.Setpgid = false
+		attrs.Setsid = true
+		attrs.Setctty = true
+		attrs.Ctty = 0
+	}
+
+	// Drop the server's privileges if the job has its own identity
+	if j.credential != nil {
+		attrs.Credential = j.credential.sysProcCredential()
+	}
+
 	// Execute the process with the constraints of the new cgroup
 	if j.cgroup != nil {
 		attrs.UseCgroupFD = true
@@ -153,39 +454,67 @@ func (j *Job) start(ctx context.Context) error {
 	cmd.SysProcAttr = attrs
 
 	// Starts running the job
-	if err := cmd.Start(); err != nil {
-		j.stop(JobScheduled, JobFailedToStart)
-		return fmt.Errorf("failed starting command for %s: %w", j.jobID, err)
+	err = cmd.Start()
+
+	// The read end of stdin, the output and the terminal's slave belong to
+	// the process now
+	if stdin != nil {
+		stdin.Close()
+	}
+	if stdout != nil {
+		stdout.Close()
+		stderr.Close()
+	}
+	if terminal != nil {
+		terminal.Close()
+	}
+
+	if err != nil {
+		return j.failStart(fmt.Errorf("failed starting command for %s: %w", j.jobID, err))
 	}
 
 	log.Printf("Registering pid=%d for job %s", cmd.Process.Pid, j.jobID)
+	startedAt := time.Now()
+	if j.procStartTime, err = processStartTime(cmd.Process.Pid); err != nil {
+		log.Printf("Job %s: %v", j.jobID, err)
+	}
 	j.pid.Store(int32(cmd.Process.Pid))
+	j.startedAt.Store(startedAt.UnixNano())
 	j.status.Store(int32(JobRunning))
+	j.statusChanged(JobRunning)
 
 	// Start a goroutine to monitor the process
 	go j.monitorCommand(cmd)
 
+	// The log may have filled before the job was running, when stopping it
+	// had no effect
+	if j.logLimitExceeded() {
+		if err := j.terminate(); err != nil {
+			log.Printf("Failed stopping job %s: %v", j.jobID, err)
+		}
+	}
+
+	if j.timeout > 0 {
+		deadline := startedAt.Add(j.timeout)
+		j.deadline.Store(deadline.UnixNano())
+		go j.enforceTimeout(deadline)
+	}
+
 	return nil
 }
 
 // initCgroup:
 // - Creates the cgroup (mkdir $cgroupPath/$name).
-// - Sets the limits for the cgroup according to job.limit.
+// - Sets the limits for the cgroup according to job.limits.
 func (j *Job) initCgroup() error {
 	// This should be nil in `go test` so we won't need root priviledges
 	if j.cgroup == nil {
 		return nil
 	}
 
-	limits := &ResourceLimits{
-		CPUMaxQuotaMicroSec: jobWorkerCPUMaxQuotaMicroSec,
-		MemMaxBytes:         jobWorkerMemMaxBytes,
-		IOMaxBytesPerSec:    jobWorkerIoMaxBps,
-	}
-
-	log.Printf("Initializing cgroup with limits %v", limits)
+	log.Printf("Initializing cgroup with limits %+v", j.limits)
 
-	if err := j.cgroup.Create(limits); err != nil {
+	if err := j.cgroup.Create(&j.limits); err != nil {
 		return fmt.Errorf("failed creating cgroup: %w", err)
 	}
 
@@ -195,36 +524,109 @@ func (j *Job) initCgroup() error {
 // monitorCommand:
 // - Runs in a goroutine.
 // - Waits for the command to finish.
-// - Registers the exitCode.
+// - Registers the exitCode and the reason the job terminated.
+// - Makes sure nothing is left running if the job was stopped.
 // - Cleans up the job (deletes cgroups, closes files etc).
 func (j *Job) monitorCommand(cmd *exec.Cmd) {
 	err := cmd.Wait()
+	j.finishedAt.Store(time.Now().UnixNano())
 	exitCode := cmd.ProcessState.ExitCode()
 	j.exitCode.Store(int32(exitCode))
+	j.processUsage = newProcessUsage(cmd.ProcessState)
 
 	log.Printf("Job cmd.Wait for %s returned %v, exitCode=%d", j.jobID, err, exitCode)
-	// The process ended somehow, either gracefully or by calling its cancelFunc.
+
+	j.cancelFunc()
+
+	j.stopMu.Lock()
+	close(j.exited)
+	j.setTerminationReason(cmd.ProcessState)
+	j.stopMu.Unlock()
+
+	// A stopped job ends with all of its processes, not just the main one
+	if deadline := j.stopDeadline.Load(); deadline != 0 {
+		j.reapProcesses(time.Unix(0, deadline))
+	}
+
+	// Make sure all of the output reached the logfile before the job is
+	// marked as stopped and its streams end
+	if err := j.closeOutput(); err != nil {
+		log.Printf("Job %s: %v", j.jobID, err)
+	}
+
+	// The process ended somehow, either gracefully or by being stopped.
 	// We need to clean up its resources (mainly cgroup), update its status to stopped,
-	// and close the file.  The close file event will trigger an inotify CLOSE_WRITE
-	// event which in turn will close the the stream's outputChannel
+	// and close the file.  Closing the file closes the job's broadcaster, so its
+	// streams end once they sent the rest of the output
 
 	err = j.stop(JobRunning, JobStopped)
 	log.Printf("Job stop for %s returned %v", j.jobID, err)
 }
 
+// openTerminal:
+// - Opens a pseudo terminal if the job requested one.
+// - Starts copying its output to the logfile.
+// - Returns the slave side which should be passed to the command.
+func (j *Job) openTerminal() (*os.File, error) {
+	if !j.tty {
+		return nil, nil
+	}
+
+	terminal, slave, err := openPty()
+	if err != nil {
+		return nil, err
+	}
+	j.pty = terminal
+
+	// Once the slave is closed by us and by the process, the copy ends
+	go j.pty.copyOutput(j.output.stream(OutputStdout))
+
+	if j.ttyRows > 0 && j.ttyCols > 0 {
+		if err := j.pty.resize(j.ttyRows, j.ttyCols); err != nil {
+			slave.Close()
+			return nil, err
+		}
+	}
+
+	return slave, nil
+}
+
 func (j *Job) openLogFile() error {
 	// ensure logdir exists
-	if err := os.MkdirAll(jobWorkerManagerLogDir, jobWorkerLogDirPerms); err != nil {
-		return fmt.Errorf("failed creating log directory %s: %w", jobWorkerManagerLogDir, err)
+	if err := os.MkdirAll(j.logDir, jobWorkerLogDirPerms); err != nil {
+		return fmt.Errorf("failed creating log directory %s: %w", j.logDir, err)
 	}
 
 	// open our log file
-	logPath := filepath.Join(jobWorkerManagerLogDir, j.jobID+".log")
+	logPath := filepath.Join(j.logDir, j.jobID+".log")
 	logFile, err := os.Create(logPath)
 	if err != nil {
 		return fmt.Errorf("failed to create logfile %s: %w", logPath, err)
 	}
-	j.logFile = logFile
+	j.logPath = logPath
+
+	if _, err := logFile.Write(appendLogHeader(nil, 0)); err != nil {
+		logFile.Close()
+		return fmt.Errorf("failed writing header of logfile %s: %w", logPath, err)
+	}
+
+	j.output = &logWriter{
+		path:        logPath,
+		file:        logFile,
+		size:        int64(logHeaderSize),
+		maxBytes:    j.logLimit,
+		policy:      j.logPolicy,
+		credential:  j.credential,
+		full:        j.logFull,
+		broadcaster: newOutputBroadcaster(0),
+	}
+
+	// The log belongs to the user the job runs as
+	if j.credential != nil {
+		if err := logFile.Chown(int(j.credential.UID), int(j.credential.GID)); err != nil {
+			return fmt.Errorf("failed changing owner of logfile %s: %w", logPath, err)
+		}
+	}
 
 	return nil
 }
@@ -234,21 +636,56 @@ func (j *Job) stop(oldStatus, status JobStatus) error {
 		return fmt.Errorf("unexpcted status for job %s: %v", oldStatus, status)
 	}
 
-	if j.logFile != nil {
-		if err := j.logFile.Close(); err != nil {
-			return fmt.Errorf("failed closing logfile: %w", err)
+	// Watchers learn about the new status once the job is cleaned up
+	defer j.statusChanged(status)
+
+	// Streams that follow the output end even if the job isn't cleaned up
+	if j.output != nil {
+		defer j.output.broadcaster.close()
+	}
+
+	if err := j.closeStdin(); err != nil {
+		return err
+	}
+
+	if err := j.closeOutput(); err != nil {
+		return err
+	}
+
+	if j.output != nil {
+		if err := j.output.Close(); err != nil {
+			return err
 		}
 	}
 
+	// The cgroup's counters are gone once it is deleted
+	if status == JobStopped {
+		j.snapshotUsage()
+	}
+
 	if j.cgroup != nil {
 		if err := j.cgroup.Delete(); err != nil {
 			return fmt.Errorf("deleting cgroup for job failed: %w", err)
 		}
 	}
 
+	if status == JobStopped && j.logPath != "" {
+		j.recordLogSize()
+		if j.compression != CompressionNone {
+			go j.compressLog(j.compression)
+		}
+	}
+
 	return nil
 }
 
+// statusChanged persists the job and notifies its watchers, it must be
+// called after every status transition
+func (j *Job) statusChanged(status JobStatus) {
+	j.persist()
+	j.notifyStatus(status)
+}
+
 func (j *Job) isActive() bool {
 	status := j.Status()
 	return status == JobRunning || status == JobScheduled
@@ -0,0 +1,155 @@
+package manager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultListPageSize = 100
+	maxListPageSize     = 1000
+)
+
+// JobFilter selects jobs in ListJobs, empty fields match every job.
+type JobFilter struct {
+	Owner         string
+	Statuses      []JobStatus
+	Command       string
+	StartedAfter  time.Time
+	StartedBefore time.Time
+	Labels        map[string]string
+}
+
+// matches returns true if the job passes all the filter's conditions
+func (f *JobFilter) matches(job *JobInfo) bool {
+	if f.Owner != "" && job.owner != f.Owner {
+		return false
+	}
+
+	if len(f.Statuses) > 0 && !f.matchesStatus(job.Status()) {
+		return false
+	}
+
+	// The command may be given either as it was requested or by its basename
+	if f.Command != "" && job.command != f.Command && filepath.Base(job.command) != f.Command {
+		return false
+	}
+
+	if !f.StartedAfter.IsZero() || !f.StartedBefore.IsZero() {
+		startedAt := job.StartedAt()
+		if startedAt.IsZero() {
+			return false
+		}
+		if !f.StartedAfter.IsZero() && startedAt.Before(f.StartedAfter) {
+			return false
+		}
+		if !f.StartedBefore.IsZero() && !startedAt.Before(f.StartedBefore) {
+			return false
+		}
+	}
+
+	for k, v := range f.Labels {
+		if label, ok := job.labels[k]; !ok || label != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *JobFilter) matchesStatus(status JobStatus) bool {
+	for _, s := range f.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ListJobs:
+//   - Collects all the jobs that match the filter.
+//   - Sorts them by creation time (and job ID for jobs created at the same
+//     time) so that paging is stable while new jobs are being added.
+//   - Returns up to pageSize jobs following the pageToken, along with the
+//     token for the next page, which is empty on the last page.
+func (m *JobManager) ListJobs(filter *JobFilter, pageSize int, pageToken string) ([]*JobInfo, string, error) {
+	if pageSize <= 0 {
+		pageSize = defaultListPageSize
+	}
+	if pageSize > maxListPageSize {
+		pageSize = maxListPageSize
+	}
+
+	var after *JobInfo
+	if pageToken != "" {
+		var err error
+		if after, err = decodePageToken(pageToken); err != nil {
+			return nil, "", fmt.Errorf("invalid page token: %w", err)
+		}
+	}
+
+	var jobs []*JobInfo
+	m.jobDB.Range(func(_, value any) bool {
+		job, ok := value.(*Job)
+		if !ok {
+			return true
+		}
+		if filter == nil || filter.matches(job.JobInfo) {
+			if after == nil || jobLess(after, job.JobInfo) {
+				jobs = append(jobs, job.JobInfo)
+			}
+		}
+		return true
+	})
+
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobLess(jobs[i], jobs[k])
+	})
+
+	if len(jobs) <= pageSize {
+		return jobs, "", nil
+	}
+
+	jobs = jobs[:pageSize]
+
+	return jobs, encodePageToken(jobs[pageSize-1]), nil
+}
+
+func jobLess(a, b *JobInfo) bool {
+	// Compare the wall clock only, as page tokens carry no monotonic reading
+	if a.createdAt.UnixNano() != b.createdAt.UnixNano() {
+		return a.createdAt.UnixNano() < b.createdAt.UnixNano()
+	}
+	return a.jobID < b.jobID
+}
+
+// A page token is the position of the last job on the previous page,
+// encoded as "<createdAt unix nanoseconds>/<jobID>".
+func encodePageToken(last *JobInfo) string {
+	token := strconv.FormatInt(last.createdAt.UnixNano(), 10) + "/" + last.jobID
+	return base64.RawURLEncoding.EncodeToString([]byte(token))
+}
+
+func decodePageToken(token string) (*JobInfo, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	nsec, jobID, ok := strings.Cut(string(data), "/")
+	if !ok {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	createdAt, err := strconv.ParseInt(nsec, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed token: %w", err)
+	}
+
+	return &JobInfo{jobID: jobID, createdAt: time.Unix(0, createdAt)}, nil
+}
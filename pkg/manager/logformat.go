@@ -0,0 +1,230 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// logMagic starts every segment of a job's log, logs without it were
+	// written before output was framed and hold raw stdout
+	logMagic   = "\x00JWLOG"
+	logVersion = 2
+	// logHeaderSize is the size of the header of each segment, which holds
+	// the magic, the version and the offset of the segment's first output
+	logHeaderSize = len(logMagic) + 2 + 8
+	// frameHeaderSize is the size of the header of each frame, which holds
+	// the stream the output came from, the time it was written at and the
+	// size of its data
+	frameHeaderSize = 1 + 8 + 4
+	// maxFrameSize bounds the data of a single frame, larger writes are
+	// split in several frames
+	maxFrameSize = 1 << 20
+)
+
+// OutputStream tells which of the job's standard streams output came from
+type OutputStream int32
+
+const (
+	OutputStdout OutputStream = iota
+	OutputStderr
+)
+
+func (s OutputStream) String() string {
+	return [...]string{"Stdout", "Stderr"}[s]
+}
+
+// OutputChunk is a piece of the job's output along with its stream
+type OutputChunk struct {
+	Stream OutputStream
+	Data   []byte
+	// Offset is the position of Data in the job's output, counting both
+	// streams, so a stream can be resumed from Offset+len(Data)
+	Offset int64
+	// Time is when the output was written to the log, zero for logs
+	// without frames
+	Time time.Time
+}
+
+// appendLogHeader appends the header of a segment whose output starts at
+// offset to buf
+func appendLogHeader(buf []byte, offset int64) []byte {
+	buf = append(buf, logMagic...)
+	buf = append(buf, logVersion, '\n')
+	return binary.BigEndian.AppendUint64(buf, uint64(offset))
+}
+
+// appendFrame appends a frame of data from stream to buf
+func appendFrame(buf []byte, stream OutputStream, now time.Time, data []byte) []byte {
+	buf = append(buf, byte(stream))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(now.UnixNano()))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(data)))
+	return append(buf, data...)
+}
+
+type logFormat int
+
+const (
+	logFormatUnknown logFormat = iota
+	logFormatRaw
+	logFormatFramed
+)
+
+// frameReader decodes the chunks of a log segment which may still be
+// written to, so partial frames are kept until the rest of them is read.
+type frameReader struct {
+	reader  io.Reader
+	format  logFormat
+	offset  int64
+	pending []byte
+	buffer  []byte
+}
+
+func newFrameReader(reader io.Reader) *frameReader {
+	return &frameReader{reader: reader, buffer: make([]byte, readBufferSize)}
+}
+
+// Reset moves on to the next segment, whatever is left of the previous one
+// is dropped
+func (r *frameReader) Reset(reader io.Reader) {
+	r.reader = reader
+	r.format = logFormatUnknown
+	r.offset = 0
+	r.pending = r.pending[:0]
+}
+
+// ReadChunk returns the next complete chunk of the log, or io.EOF if the
+// segment doesn't hold one yet.
+func (r *frameReader) ReadChunk() (OutputChunk, error) {
+	for {
+		chunk, ok, err := r.decode()
+		if ok || err != nil {
+			return chunk, err
+		}
+
+		n, err := r.reader.Read(r.buffer)
+		r.pending = append(r.pending, r.buffer[:n]...)
+		if err != nil {
+			if chunk, ok, decodeErr := r.decode(); ok || decodeErr != nil {
+				return chunk, decodeErr
+			}
+			return OutputChunk{}, err
+		}
+	}
+}
+
+// decode returns the first chunk held by pending, if it is complete
+func (r *frameReader) decode() (OutputChunk, bool, error) {
+	if r.format == logFormatUnknown {
+		ok, err := r.decodeHeader()
+		if !ok || err != nil {
+			return OutputChunk{}, false, err
+		}
+	}
+
+	if r.format == logFormatRaw {
+		if len(r.pending) == 0 {
+			return OutputChunk{}, false, nil
+		}
+		chunk := OutputChunk{Stream: OutputStdout, Data: append([]byte(nil), r.pending...), Offset: r.offset}
+		r.offset += int64(len(chunk.Data))
+		r.consume(len(r.pending))
+		return chunk, true, nil
+	}
+
+	if len(r.pending) < frameHeaderSize {
+		return OutputChunk{}, false, nil
+	}
+
+	stream := OutputStream(r.pending[0])
+	nsec := int64(binary.BigEndian.Uint64(r.pending[1:9]))
+	size := binary.BigEndian.Uint32(r.pending[9:frameHeaderSize])
+	if stream > OutputStderr || size > maxFrameSize {
+		return OutputChunk{}, false, fmt.Errorf("corrupted frame of stream %d with %d bytes", stream, size)
+	}
+
+	end := frameHeaderSize + int(size)
+	if len(r.pending) < end {
+		return OutputChunk{}, false, nil
+	}
+
+	chunk := OutputChunk{
+		Stream: stream,
+		Data:   append([]byte(nil), r.pending[frameHeaderSize:end]...),
+		Offset: r.offset,
+		Time:   time.Unix(0, nsec),
+	}
+	r.offset += int64(size)
+	r.consume(end)
+
+	return chunk, true, nil
+}
+
+// decodeHeader tells framed segments from raw ones, once enough of the
+// segment was read
+func (r *frameReader) decodeHeader() (bool, error) {
+	if !bytes.HasPrefix(r.pending, []byte(logMagic)) {
+		if bytes.HasPrefix([]byte(logMagic), r.pending) {
+			return false, nil
+		}
+		r.format = logFormatRaw
+		return true, nil
+	}
+
+	if len(r.pending) < logHeaderSize {
+		return false, nil
+	}
+
+	if version := r.pending[len(logMagic)]; version != logVersion {
+		return false, fmt.Errorf("unsupported log version %d", version)
+	}
+
+	r.format = logFormatFramed
+	r.offset = int64(binary.BigEndian.Uint64(r.pending[len(logMagic)+2 : logHeaderSize]))
+	r.consume(logHeaderSize)
+
+	return true, nil
+}
+
+func (r *frameReader) consume(n int) {
+	r.pending = append(r.pending[:0], r.pending[n:]...)
+}
+
+// logEnd returns the offset of the output that follows the log segment in
+// file, it only reads the headers of its frames.
+func logEnd(file *os.File) (int64, error) {
+	header := make([]byte, logHeaderSize)
+	n, err := file.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("failed reading log header: %w", err)
+	}
+
+	if n < logHeaderSize || !bytes.HasPrefix(header, []byte(logMagic)) {
+		info, err := file.Stat()
+		if err != nil {
+			return 0, fmt.Errorf("failed reading log: %w", err)
+		}
+		return info.Size(), nil
+	}
+
+	offset := int64(binary.BigEndian.Uint64(header[len(logMagic)+2:]))
+	frameHeader := make([]byte, frameHeaderSize)
+
+	for pos := int64(logHeaderSize); ; {
+		if _, err := file.ReadAt(frameHeader, pos); err != nil {
+			if errors.Is(err, io.EOF) {
+				return offset, nil
+			}
+			return 0, fmt.Errorf("failed reading log frame: %w", err)
+		}
+
+		size := int64(binary.BigEndian.Uint32(frameHeader[9:]))
+		offset += size
+		pos += frameHeaderSize + size
+	}
+}
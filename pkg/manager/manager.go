@@ -3,43 +3,189 @@ package manager
 import (
 	"context"
 	"fmt"
+	"log"
+	"os"
 	"sync"
+	"time"
 )
 
 // JobManager is the main struct for the package.
 // jobDB is our in memory database, it looks like {"jobID" : *Job}
+// store, if set, persists jobDB across restarts.
 type JobManager struct {
-	jobDB   sync.Map
-	watcher *LogWatcher
+	jobDB  sync.Map
+	store  JobStore
+	logDir string
+	// cgroupRoot is where the jobs' cgroups are created
+	cgroupRoot  string
+	retention   RetentionPolicy
+	deleteHook  func(*JobInfo)
+	compression LogCompression
+	// done is closed when the manager is closed, to stop its reaper
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
 }
 
-func NewJobManager() (*JobManager, error) {
-	watcher, err := NewLogWatcher()
+type ManagerOption func(*JobManager)
+
+// WithJobStore persists the jobs in store, and restores the jobs it holds
+// when the manager is created.
+func WithJobStore(store JobStore) ManagerOption {
+	return func(m *JobManager) {
+		if store != nil {
+			m.store = &managerStore{JobStore: store}
+		}
+	}
+}
+
+// WithCgroupRoot sets the cgroup the jobs' cgroups are created under, which
+// is scanned for leftovers of previous managers when using a job store.
+func WithCgroupRoot(root string) ManagerOption {
+	return func(m *JobManager) {
+		m.cgroupRoot = root
+	}
+}
+
+// WithLogDir sets the directory the jobs' output is written to.
+func WithLogDir(dir string) ManagerOption {
+	return func(m *JobManager) {
+		m.logDir = dir
+	}
+}
+
+func NewJobManager(opts ...ManagerOption) (*JobManager, error) {
+	m := &JobManager{
+		logDir:     jobWorkerManagerLogDir,
+		cgroupRoot: cgroupSysFsRoot,
+		done:       make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if err := m.restoreJobs(); err != nil {
+		return nil, fmt.Errorf("failed restoring jobs: %w", err)
+	}
+
+	if m.retention.enabled() {
+		go m.reapJobs()
+	}
+
+	return m, nil
+}
+
+// restoreJobs:
+//   - Loads the jobs from the store, if the manager has one.
+//   - Jobs that were still running when the previous manager went away are
+//     adopted if their process is alive, otherwise they are marked as lost.
+//   - Removes the cgroups of jobs that are no longer running.
+func (m *JobManager) restoreJobs() error {
+	if m.store == nil {
+		return nil
+	}
+
+	records, err := m.store.Load()
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize log watcher: %w", err)
+		return err
 	}
 
-	return &JobManager{
-		watcher: watcher,
-	}, nil
+	adopted := 0
+	for _, record := range records {
+		job := jobFromRecord(record)
+		job.store = m.store
+		job.compression = m.compression
+
+		if !job.Status().isTerminal() {
+			if job.Status() == JobRunning && job.adopt() {
+				adopted++
+			} else {
+				log.Printf("Job %s was %v when the manager went away, marking it as lost", job.jobID, job.Status())
+
+				job.reason.Store(int32(TerminationLost))
+				job.finishedAt.Store(time.Now().UnixNano())
+				job.status.Store(int32(JobStopped))
+				job.persist()
+
+				// Nobody is left to read the job's output
+				if job.logPath != "" {
+					job.removeOutputPipes()
+				}
+			}
+		}
+
+		// Logs of jobs that stopped before they were compressed, e.g if
+		// compression was just enabled, are compressed now
+		if job.Status() == JobStopped && job.compression != CompressionNone && job.LogCompression() == CompressionNone {
+			go job.compressLog(job.compression)
+		}
+
+		m.jobDB.Store(job.jobID, job)
+	}
+
+	log.Printf("Restored %d jobs, %d of them are still running", len(records), adopted)
+
+	if _, err := os.Stat(m.cgroupRoot); err == nil {
+		return m.cleanupStaleCgroups()
+	}
+
+	return nil
+}
+
+// Jobs returns all of the jobs known to the manager
+func (m *JobManager) Jobs() []*JobInfo {
+	var jobs []*JobInfo
+
+	m.jobDB.Range(func(_, value any) bool {
+		if job, ok := value.(*Job); ok {
+			jobs = append(jobs, job.JobInfo)
+		}
+		return true
+	})
+
+	return jobs
+}
+
+// Close stops the manager's reaper, and releases its store.  Jobs that are
+// still being cleaned up or compressed are no longer saved, and closing
+// the manager again does nothing.
+func (m *JobManager) Close() error {
+	m.closeOnce.Do(func() {
+		close(m.done)
+
+		if m.store != nil {
+			m.closeErr = m.store.Close()
+		}
+	})
+
+	return m.closeErr
 }
 
 // StartJob:
 //   - Stores the job in our db
 //   - Runs the job
 func (m *JobManager) StartJob(ctx context.Context, command string, args []string, opts ...JobOption) (*JobInfo, error) {
+	// The manager's cgroup root comes first, so it can be overridden
+	opts = append([]JobOption{withCgroupRoot(m.cgroupRoot)}, opts...)
+
 	job, err := NewJob(command, args, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create job: %w", err)
 	}
+	job.logDir = m.logDir
+	job.store = m.store
+	job.compression = m.compression
 
 	// Make sure we didn't call StartJob on this job already
 	if _, loaded := m.jobDB.LoadOrStore(job.jobID, job); loaded {
 		return nil, fmt.Errorf("cannot reuse job id %s", job.JobID())
 	}
 
+	// The job is returned even if it failed to start, so the reason can
+	// be inspected
 	if err := job.start(ctx); err != nil {
-		return nil, fmt.Errorf("job %s failed to start: %w", job.jobID, err)
+		return job.JobInfo, fmt.Errorf("job %s failed to start: %w", job.jobID, err)
 	}
 
 	return job.JobInfo, nil
@@ -47,8 +193,9 @@ func (m *JobManager) StartJob(ctx context.Context, command string, args []string
 
 // StopJob:
 //   - Loads the job by its jobID
-//   - Calls the command's context cancelFunc
-func (m *JobManager) StopJob(jobID string) (*JobInfo, error) {
+//   - Signals all of the job's processes, SIGTERM by default
+//   - Kills whatever is left of the job after the grace period
+func (m *JobManager) StopJob(jobID string, opts ...StopOption) (*JobInfo, error) {
 	j, ok := m.jobDB.Load(jobID)
 	if !ok {
 		return nil, fmt.Errorf("job %s was not found in memory", jobID)
@@ -59,14 +206,16 @@ func (m *JobManager) StopJob(jobID string) (*JobInfo, error) {
 		return nil, fmt.Errorf("type assertion failed for job %s", jobID)
 	}
 
-	// Cancel the command's context which will kill the process
-	job.cancelFunc()
+	if err := job.terminate(opts...); err != nil {
+		return nil, fmt.Errorf("failed stopping job %s: %w", jobID, err)
+	}
 
 	return job.JobInfo, nil
 }
 
 // QueryJob:
 //   - Loads the job by jobID
+//   - Refreshes the resource usage of a running job from its cgroup
 //   - Returns the job's status
 func (m *JobManager) QueryJob(jobID string) (*JobInfo, error) {
 	j, ok := m.jobDB.Load(jobID)
@@ -79,17 +228,147 @@ func (m *JobManager) QueryJob(jobID string) (*JobInfo, error) {
 		return nil, fmt.Errorf("type assertion failed for job %s", jobID)
 	}
 
+	job.refreshUsage()
+
 	return job.JobInfo, nil
 }
 
+// StreamOption configures the output StreamJob sends
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	streams   []OutputStream
+	offset    int64
+	tailLines int
+	since     time.Time
+	follow    bool
+}
+
+// WithStreams only streams the output of streams, e.g OutputStderr, all
+// of them are streamed by default.
+func WithStreams(streams ...OutputStream) StreamOption {
+	return func(o *streamOptions) {
+		o.streams = append(o.streams, streams...)
+	}
+}
+
+// WithOffset starts the stream at offset of the job's output, e.g the
+// offset that follows the last chunk of a previous stream.  Output that was
+// dropped by rotation is skipped.
+func WithOffset(offset int64) StreamOption {
+	return func(o *streamOptions) {
+		o.offset = offset
+	}
+}
+
+// WithTail starts the stream at the last lines of the job's output.
+func WithTail(lines int) StreamOption {
+	return func(o *streamOptions) {
+		o.tailLines = lines
+	}
+}
+
+// WithSince starts the stream at the output written at or after since.
+func WithSince(since time.Time) StreamOption {
+	return func(o *streamOptions) {
+		o.since = since
+	}
+}
+
+// WithFollow keeps on streaming the job's output until it stops, which is
+// the default.  Otherwise the stream ends once the output that was already
+// written is sent.
+func WithFollow(follow bool) StreamOption {
+	return func(o *streamOptions) {
+		o.follow = follow
+	}
+}
+
 // StreamJob:
 //   - Loads the job by jobID
-//   - Adds the job's log file to the logwatcher
-//   - Wait for the job to stop by reading from the job's doneChannel.
-//     When the job stops, its monitor goroutine will push a struct to
-//     its doneChannel.
-//   - Once the job is done, we remove the watch from the logwatcher.
-func (m *JobManager) StreamJob(jobID string) (<-chan []byte, error) {
+//   - Opens the job's log file, after its rotated segment
+//   - Sends the job's output in the order it was read from its streams,
+//     each chunk is tagged with the stream it came from and its offset
+//   - The stream starts at the latest of the positions given by the
+//     offset, tail and since options, or at the start of the log
+//   - Once the log was read, the output of a running job is received from
+//     its broadcaster, which keeps the latest output in memory for all of
+//     the job's streams
+//   - The channel is closed once the job stopped and all of its output was
+//     sent, or once the log was read if the stream doesn't follow the job
+//   - Cancelling ctx closes the channel right away and releases the stream,
+//     which must be done by callers that stop reading the channel early
+func (m *JobManager) StreamJob(ctx context.Context, jobID string, opts ...StreamOption) (<-chan OutputChunk, error) {
+	j, ok := m.jobDB.Load(jobID)
+	if !ok {
+		return nil, fmt.Errorf("job %s was not found in memory", jobID)
+	}
+
+	job, ok := j.(*Job)
+	if !ok {
+		return nil, fmt.Errorf("type assertion failed for job %s", jobID)
+	}
+
+	if job.logPath == "" {
+		return nil, fmt.Errorf("job %s has no output", jobID)
+	}
+
+	options := streamOptions{follow: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.offset < 0 || options.tailLines < 0 {
+		return nil, fmt.Errorf("invalid stream offset %d or tail %d", options.offset, options.tailLines)
+	}
+
+	segments := logSegments(job.logPath)
+	watchOpts := []watchOption{
+		withHistory(segments[:len(segments)-1]...),
+		withStreams(options.streams...),
+		withOffset(options.offset),
+		withTail(options.tailLines),
+		withSince(options.since),
+	}
+
+	// The log isn't written while its segments are opened, so the
+	// broadcaster picks up where the log ends.  Without following, the
+	// stream ends after it read what's in the log.
+	if job.output != nil {
+		job.output.mu.Lock()
+		defer job.output.mu.Unlock()
+
+		if options.follow {
+			watchOpts = append(watchOpts, withBroadcaster(job.output.broadcaster))
+		}
+	}
+
+	return watchLog(ctx, job.logPath, watchOpts...)
+}
+
+// AttachStdin:
+//   - Loads the job by jobID
+//   - Returns a writer for the job's stdin, only a single writer may be
+//     attached at a time until it is detached
+func (m *JobManager) AttachStdin(jobID string) (*StdinWriter, error) {
+	j, ok := m.jobDB.Load(jobID)
+	if !ok {
+		return nil, fmt.Errorf("job %s was not found in memory", jobID)
+	}
+
+	job, ok := j.(*Job)
+	if !ok {
+		return nil, fmt.Errorf("type assertion failed for job %s", jobID)
+	}
+
+	return job.attachStdin()
+}
+
+// WatchJob:
+//   - Loads the job by jobID
+//   - Returns a channel with the job's current status followed by its
+//     transitions, it is closed once the job stops or ctx is done
+func (m *JobManager) WatchJob(ctx context.Context, jobID string) (<-chan JobStatus, error) {
 	j, ok := m.jobDB.Load(jobID)
 	if !ok {
 		return nil, fmt.Errorf("job %s was not found in memory", jobID)
@@ -100,5 +379,5 @@ func (m *JobManager) StreamJob(jobID string) (<-chan []byte, error) {
 		return nil, fmt.Errorf("type assertion failed for job %s", jobID)
 	}
 
-	return m.watcher.AddWatch(job.logFile.Name(), job.isActive)
+	return job.watchStatus(ctx), nil
 }
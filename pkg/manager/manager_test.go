@@ -1,16 +1,27 @@
 package manager_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"jobworker/pkg/manager"
 
+	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sys/unix"
 )
 
 func checkStatus(t *testing.T, mgr *manager.JobManager, jobID string, expected manager.JobStatus) {
@@ -27,14 +38,14 @@ func checkStatus(t *testing.T, mgr *manager.JobManager, jobID string, expected m
 }
 
 func checkStreamContains(mgr *manager.JobManager, jobID, exptected string) error {
-	outputChannel, err := mgr.StreamJob(jobID)
+	outputChannel, err := mgr.StreamJob(context.Background(), jobID)
 	if err != nil {
 		return fmt.Errorf("failed to stream job: %w", err)
 	}
 
 	output := ""
-	for line := range outputChannel {
-		output += string(line[:len(line)-1])
+	for chunk := range outputChannel {
+		output += string(chunk.Data[:len(chunk.Data)-1])
 	}
 
 	log.Printf("Received output [%v]", output)
@@ -122,3 +133,1533 @@ func TestLongRunningJob(t *testing.T) {
 
 	checkStatus(t, mgr, job.JobID(), manager.JobStopped)
 }
+
+func TestStopJob(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	// The job exits on SIGINT, but leaves a process that ignores it behind
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	script := fmt.Sprintf(
+		`trap 'echo got int; exit 0' INT; (trap '' INT TERM; echo $BASHPID > %s; sleep 100) & while true; do sleep 0.1; done`,
+		pidFile)
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", script},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	_, err = mgr.StopJob(job.JobID(), manager.WithStopSignal(unix.SIGINT), manager.WithGracePeriod(time.Second))
+	if err != nil {
+		t.Fatalf("Failed to stop job: %v", err)
+	}
+
+	if err := checkStreamContains(mgr, job.JobID(), "got int"); err != nil {
+		t.Fatalf("Check stream failed: %v", err)
+	}
+
+	checkStatus(t, mgr, job.JobID(), manager.JobStopped)
+
+	if job.ExitCode() != 0 {
+		t.Fatalf("expected exit code 0, received %d", job.ExitCode())
+	}
+
+	if job.TerminationReason() != manager.TerminationStopped {
+		t.Fatalf("expected job to be stopped by the user, received %v", job.TerminationReason())
+	}
+
+	// The process that ignored the signal should be killed after the grace
+	// period, it may still be a zombie until it is reaped
+	pid, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("Failed reading pid file: %v", err)
+	}
+
+	stat, err := os.ReadFile(filepath.Join("/proc", strings.TrimSpace(string(pid)), "stat"))
+	if err == nil && !strings.Contains(string(stat), ") Z ") {
+		t.Fatalf("expected the job's processes to be killed, found [%s]", stat)
+	}
+}
+
+func TestJobTimeout(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"sleep",
+		[]string{"100"},
+		manager.WithTimeout(time.Second),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	if job.Deadline().Sub(job.StartedAt()) != time.Second {
+		t.Fatalf("expected deadline a second after %v, received %v", job.StartedAt(), job.Deadline())
+	}
+
+	if remaining := job.Remaining(); remaining <= 0 || remaining > time.Second {
+		t.Fatalf("unexpected remaining time %v", remaining)
+	}
+
+	if err := checkStreamContains(mgr, job.JobID(), ""); err != nil {
+		t.Fatalf("Check stream failed: %v", err)
+	}
+
+	checkStatus(t, mgr, job.JobID(), manager.JobStopped)
+
+	if !job.TimedOut() || job.Remaining() != 0 {
+		t.Fatalf("expected job to time out, timedOut=%v remaining=%v", job.TimedOut(), job.Remaining())
+	}
+
+	if job.TerminationReason() != manager.TerminationTimedOut {
+		t.Fatalf("expected termination reason TimedOut, received %v", job.TerminationReason())
+	}
+}
+
+func TestJobTerminationReason(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	tests := []struct {
+		args   []string
+		reason manager.TerminationReason
+		signal unix.Signal
+	}{
+		{[]string{"-c", "exit 3"}, manager.TerminationExited, 0},
+		{[]string{"-c", "kill -USR1 $$"}, manager.TerminationSignaled, unix.SIGUSR1},
+	}
+
+	for _, test := range tests {
+		job, err := mgr.StartJob(
+			context.Background(),
+			"bash",
+			test.args,
+			manager.WithCgroup(nil),
+			manager.WithCloneFlags(0),
+		)
+		if err != nil {
+			t.Fatalf("Failed starting job: %v", err)
+		}
+
+		if err := checkStreamContains(mgr, job.JobID(), ""); err != nil {
+			t.Fatalf("Check stream failed: %v", err)
+		}
+
+		if job.TerminationReason() != test.reason || job.Signal() != test.signal {
+			t.Fatalf("%v: expected %v/%v, received %v/%v",
+				test.args, test.reason, test.signal, job.TerminationReason(), job.Signal())
+		}
+	}
+
+	job, err := mgr.StartJob(context.Background(), "no-such-command", nil, manager.WithCgroup(nil))
+	if err == nil {
+		t.Fatalf("expected a missing command to fail")
+	}
+
+	if job.TerminationReason() != manager.TerminationFailedToStart || job.ErrorMessage() == "" {
+		t.Fatalf("expected job to fail to start, received %v [%s]", job.TerminationReason(), job.ErrorMessage())
+	}
+
+	checkStatus(t, mgr, job.JobID(), manager.JobFailedToStart)
+}
+
+func TestJobUsage(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager(manager.WithLogDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", "for ((i = 0; i < 200000; i++)); do :; done"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	// Without a cgroup there's nothing to read until the process exited
+	if job.Status() == manager.JobRunning && job.Usage() != nil {
+		t.Fatalf("expected no usage for a job without a cgroup, received %+v", job.Usage())
+	}
+
+	waitStopped(t, mgr, job.JobID())
+
+	usage := job.Usage()
+	if usage == nil || usage.Process == nil {
+		t.Fatalf("expected the usage of the stopped job, received %+v", usage)
+	}
+
+	if usage.Process.UserTime+usage.Process.SystemTime <= 0 || usage.Process.MaxRSS <= 0 {
+		t.Fatalf("expected the rusage of the process, received %+v", usage.Process)
+	}
+}
+
+func TestWatchJob(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"sleep",
+		[]string{"0.5"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	// A canceled watch is closed even though the job is still running
+	ctx, cancel := context.WithCancel(context.Background())
+	canceledCh, err := mgr.WatchJob(ctx, job.JobID())
+	if err != nil {
+		t.Fatalf("Failed watching job: %v", err)
+	}
+	cancel()
+
+	watchCh, err := mgr.WatchJob(context.Background(), job.JobID())
+	if err != nil {
+		t.Fatalf("Failed watching job: %v", err)
+	}
+
+	var statuses []manager.JobStatus
+	for status := range watchCh {
+		statuses = append(statuses, status)
+	}
+
+	if fmt.Sprint(statuses) != "[Running Stopped]" {
+		t.Fatalf("expected statuses [Running Stopped], received %v", statuses)
+	}
+
+	for status := range canceledCh {
+		if status != manager.JobRunning {
+			t.Fatalf("canceled watch received %v", status)
+		}
+	}
+
+	// Watching a stopped job returns its final status only
+	watchCh, err = mgr.WatchJob(context.Background(), job.JobID())
+	if err != nil {
+		t.Fatalf("Failed watching job: %v", err)
+	}
+
+	if status, ok := <-watchCh; !ok || status != manager.JobStopped {
+		t.Fatalf("expected status Stopped, received %v", status)
+	}
+
+	if _, ok := <-watchCh; ok {
+		t.Fatalf("expected watch to be closed")
+	}
+}
+
+func TestJobStore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "jobs.journal")
+
+	newManager := func() *manager.JobManager {
+		store, err := manager.NewJournalStore(journalPath)
+		if err != nil {
+			t.Fatalf("Failed opening store: %v", err)
+		}
+
+		mgr, err := manager.NewJobManager(
+			manager.WithJobStore(store),
+			manager.WithLogDir(dir),
+			manager.WithCgroupRoot(t.TempDir()),
+		)
+		if err != nil {
+			t.Fatalf("Failed creating manager: %v", err)
+		}
+		return mgr
+	}
+
+	mgr := newManager()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", "echo hello; exit 2"},
+		manager.WithOwner("alice"),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	if err := checkStreamContains(mgr, job.JobID(), "hello"); err != nil {
+		t.Fatalf("Check stream failed: %v", err)
+	}
+
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Failed closing manager: %v", err)
+	}
+
+	// A job that was running when the manager went away
+	store, err := manager.NewJournalStore(journalPath)
+	if err != nil {
+		t.Fatalf("Failed opening store: %v", err)
+	}
+	err = store.Save(&manager.JobRecord{JobID: "lost", Command: "sleep", Status: manager.JobRunning})
+	if err != nil {
+		t.Fatalf("Failed saving record: %v", err)
+	}
+	store.Close()
+
+	mgr = newManager()
+	defer mgr.Close()
+
+	restored, err := mgr.QueryJob(job.JobID())
+	if err != nil {
+		t.Fatalf("Failed querying restored job: %v", err)
+	}
+
+	if restored.Status() != manager.JobStopped || restored.ExitCode() != 2 || restored.Owner() != "alice" ||
+		!restored.CreatedAt().Equal(job.CreatedAt()) || !restored.FinishedAt().Equal(job.FinishedAt()) {
+		t.Fatalf("restored job %+v does not match %+v", restored, job)
+	}
+
+	if usage := restored.Usage(); usage == nil || !reflect.DeepEqual(usage.Process, job.Usage().Process) {
+		t.Fatalf("restored usage %+v does not match %+v", usage, job.Usage())
+	}
+
+	if err := checkStreamContains(mgr, job.JobID(), "hello"); err != nil {
+		t.Fatalf("Check stream of restored job failed: %v", err)
+	}
+
+	lost, err := mgr.QueryJob("lost")
+	if err != nil {
+		t.Fatalf("Failed querying lost job: %v", err)
+	}
+
+	if lost.Status() != manager.JobStopped || lost.TerminationReason() != manager.TerminationLost {
+		t.Fatalf("expected lost job to be stopped, received %v/%v", lost.Status(), lost.TerminationReason())
+	}
+}
+
+func TestJournalCompaction(t *testing.T) {
+	t.Parallel()
+
+	journalPath := filepath.Join(t.TempDir(), "jobs.journal")
+	store, err := manager.NewJournalStore(journalPath)
+	if err != nil {
+		t.Fatalf("Failed opening store: %v", err)
+	}
+	defer store.Close()
+
+	// Every transition appends a line, most of which become stale
+	for i := 0; i < 3000; i++ {
+		for _, jobID := range []string{"kept", "deleted"} {
+			err := store.Save(&manager.JobRecord{JobID: jobID, Command: "true", ExitCode: int32(i)})
+			if err != nil {
+				t.Fatalf("Failed saving record: %v", err)
+			}
+		}
+	}
+	if err := store.Delete("deleted"); err != nil {
+		t.Fatalf("Failed deleting record: %v", err)
+	}
+
+	data, err := os.ReadFile(journalPath)
+	if err != nil {
+		t.Fatalf("Failed reading journal: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines > 2000 {
+		t.Fatalf("expected the journal to be compacted, found %d lines", lines)
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed loading store: %v", err)
+	}
+	if len(records) != 1 || records[0].JobID != "kept" || records[0].ExitCode != 2999 {
+		t.Fatalf("expected the latest record of the kept job, received %+v", records)
+	}
+}
+
+func TestManagerClose(t *testing.T) {
+	t.Parallel()
+
+	journalPath := filepath.Join(t.TempDir(), "jobs.journal")
+	store, err := manager.NewJournalStore(journalPath)
+	if err != nil {
+		t.Fatalf("Failed opening store: %v", err)
+	}
+
+	mgr, err := manager.NewJobManager(manager.WithLogDir(t.TempDir()), manager.WithJobStore(store))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"sleep",
+		[]string{"0.2"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := mgr.Close(); err != nil {
+			t.Fatalf("Failed closing manager: %v", err)
+		}
+	}
+
+	// The job stops after the store was closed, so it is left running in
+	// the journal rather than failing to be saved
+	waitStopped(t, mgr, job.JobID())
+
+	store, err = manager.NewJournalStore(journalPath)
+	if err != nil {
+		t.Fatalf("Failed opening store: %v", err)
+	}
+	defer store.Close()
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed loading store: %v", err)
+	}
+	if len(records) != 1 || records[0].Status != manager.JobRunning {
+		t.Fatalf("expected the job to be saved as running, received %+v", records)
+	}
+}
+
+func TestJobAdopt(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cgroupRoot := t.TempDir()
+	journalPath := filepath.Join(dir, "jobs.journal")
+
+	newManager := func() *manager.JobManager {
+		store, err := manager.NewJournalStore(journalPath)
+		if err != nil {
+			t.Fatalf("Failed opening store: %v", err)
+		}
+
+		mgr, err := manager.NewJobManager(
+			manager.WithJobStore(store),
+			manager.WithLogDir(dir),
+			manager.WithCgroupRoot(cgroupRoot),
+		)
+		if err != nil {
+			t.Fatalf("Failed creating manager: %v", err)
+		}
+		return mgr
+	}
+
+	// An empty cgroup left by a job we don't know, and a cgroup which isn't ours
+	staleCgroup := filepath.Join(cgroupRoot, uuid.NewString())
+	otherCgroup := filepath.Join(cgroupRoot, "system.slice")
+	for _, path := range []string{staleCgroup, otherCgroup} {
+		if err := os.Mkdir(path, 0o755); err != nil {
+			t.Fatalf("Failed creating cgroup: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "cgroup.events"), []byte("populated 0\nfrozen 0\n"), 0o644); err != nil {
+			t.Fatalf("Failed writing cgroup.events: %v", err)
+		}
+	}
+
+	mgr := newManager()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"sleep",
+		[]string{"100"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	// The job keeps on running while the manager goes away
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Failed closing manager: %v", err)
+	}
+
+	mgr = newManager()
+	defer mgr.Close()
+
+	checkStatus(t, mgr, job.JobID(), manager.JobRunning)
+
+	if _, err := os.Stat(staleCgroup); !os.IsNotExist(err) {
+		t.Fatalf("expected stale cgroup to be removed, stat returned %v", err)
+	}
+
+	if _, err := os.Stat(otherCgroup); err != nil {
+		t.Fatalf("expected other cgroup to be kept, stat returned %v", err)
+	}
+
+	watchCh, err := mgr.WatchJob(context.Background(), job.JobID())
+	if err != nil {
+		t.Fatalf("Failed watching job: %v", err)
+	}
+
+	if _, err := mgr.StopJob(job.JobID(), manager.WithGracePeriod(0)); err != nil {
+		t.Fatalf("Failed stopping adopted job: %v", err)
+	}
+
+	for range watchCh {
+	}
+
+	adopted, err := mgr.QueryJob(job.JobID())
+	if err != nil {
+		t.Fatalf("Failed querying adopted job: %v", err)
+	}
+
+	if adopted.Status() != manager.JobStopped || adopted.TerminationReason() != manager.TerminationStopped {
+		t.Fatalf("expected adopted job to be stopped, received %v/%v", adopted.Status(), adopted.TerminationReason())
+	}
+}
+
+func waitStopped(t *testing.T, mgr *manager.JobManager, jobID string) {
+	t.Helper()
+
+	watchCh, err := mgr.WatchJob(context.Background(), jobID)
+	if err != nil {
+		t.Fatalf("Failed watching job: %v", err)
+	}
+
+	for range watchCh {
+	}
+}
+
+func TestDeleteJob(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "jobs.journal")
+
+	var deleted []string
+	newManager := func() *manager.JobManager {
+		store, err := manager.NewJournalStore(journalPath)
+		if err != nil {
+			t.Fatalf("Failed opening store: %v", err)
+		}
+
+		mgr, err := manager.NewJobManager(
+			manager.WithJobStore(store),
+			manager.WithLogDir(dir),
+			manager.WithCgroupRoot(t.TempDir()),
+			manager.WithDeleteHook(func(jobInfo *manager.JobInfo) {
+				deleted = append(deleted, jobInfo.JobID())
+			}),
+		)
+		if err != nil {
+			t.Fatalf("Failed creating manager: %v", err)
+		}
+		return mgr
+	}
+
+	mgr := newManager()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"sleep",
+		[]string{"100"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	if _, err := mgr.DeleteJob(job.JobID()); err == nil {
+		t.Fatalf("expected deleting a running job to fail")
+	}
+
+	if _, err := mgr.StopJob(job.JobID(), manager.WithGracePeriod(0)); err != nil {
+		t.Fatalf("Failed stopping job: %v", err)
+	}
+	waitStopped(t, mgr, job.JobID())
+
+	logPath := filepath.Join(dir, job.JobID()+".log")
+	if _, err := os.Stat(logPath); err != nil {
+		t.Fatalf("expected job log %s: %v", logPath, err)
+	}
+
+	if _, err := mgr.DeleteJob(job.JobID()); err != nil {
+		t.Fatalf("Failed deleting job: %v", err)
+	}
+
+	if _, err := mgr.QueryJob(job.JobID()); err == nil {
+		t.Fatalf("expected deleted job to be gone")
+	}
+
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Fatalf("expected job log to be removed, stat returned %v", err)
+	}
+
+	if len(deleted) != 1 || deleted[0] != job.JobID() {
+		t.Fatalf("expected delete hook to be called for %s, received %v", job.JobID(), deleted)
+	}
+
+	if _, err := mgr.DeleteJob(job.JobID()); err == nil {
+		t.Fatalf("expected deleting a job twice to fail")
+	}
+
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Failed closing manager: %v", err)
+	}
+
+	mgr = newManager()
+	defer mgr.Close()
+
+	if _, err := mgr.QueryJob(job.JobID()); err == nil {
+		t.Fatalf("expected deleted job not to be restored")
+	}
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager(
+		manager.WithLogDir(t.TempDir()),
+		manager.WithRetentionPolicy(manager.RetentionPolicy{
+			MaxJobsPerOwner: 1,
+			Interval:        50 * time.Millisecond,
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	var last string
+	for _, owner := range []string{"alice", "alice", "alice", "bob"} {
+		job, err := mgr.StartJob(
+			context.Background(),
+			"true",
+			nil,
+			manager.WithOwner(owner),
+			manager.WithCgroup(nil),
+			manager.WithCloneFlags(0),
+		)
+		if err != nil {
+			t.Fatalf("Failed starting job: %v", err)
+		}
+		waitStopped(t, mgr, job.JobID())
+
+		if owner == "alice" {
+			last = job.JobID()
+		}
+	}
+
+	// Only the latest job of each owner is kept
+	timeout := time.Now().Add(5 * time.Second)
+	for len(mgr.Jobs()) > 2 {
+		if time.Now().After(timeout) {
+			t.Fatalf("expected 2 jobs to be kept, found %d", len(mgr.Jobs()))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if _, err := mgr.QueryJob(last); err != nil {
+		t.Fatalf("expected latest job of alice to be kept: %v", err)
+	}
+
+	pruned := mgr.PruneJobs(&manager.JobFilter{Owner: "bob"}, time.Time{})
+	if len(pruned) != 1 || pruned[0].Owner() != "bob" {
+		t.Fatalf("expected the job of bob to be pruned, received %v", pruned)
+	}
+
+	if len(mgr.Jobs()) != 1 {
+		t.Fatalf("expected a single job to be left, found %d", len(mgr.Jobs()))
+	}
+}
+
+func streamChunks(t *testing.T, mgr *manager.JobManager, jobID string, opts ...manager.StreamOption) []manager.OutputChunk {
+	t.Helper()
+
+	outputChannel, err := mgr.StreamJob(context.Background(), jobID, opts...)
+	if err != nil {
+		t.Fatalf("Failed to stream job: %v", err)
+	}
+
+	var chunks []manager.OutputChunk
+	for chunk := range outputChannel {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+func streamOutput(t *testing.T, mgr *manager.JobManager, jobID string, opts ...manager.StreamOption) string {
+	t.Helper()
+
+	var output []byte
+	for _, chunk := range streamChunks(t, mgr, jobID, opts...) {
+		output = append(output, chunk.Data...)
+	}
+
+	return string(output)
+}
+
+// logSize returns the size of the job's log segments on disk
+func logSize(t *testing.T, dir, jobID string) int64 {
+	t.Helper()
+
+	segments, err := filepath.Glob(filepath.Join(dir, jobID+".log*"))
+	if err != nil {
+		t.Fatalf("Failed listing log segments: %v", err)
+	}
+
+	var size int64
+	for _, segment := range segments {
+		info, err := os.Stat(segment)
+		if err != nil {
+			t.Fatalf("Failed reading log segment: %v", err)
+		}
+		size += info.Size()
+	}
+
+	return size
+}
+
+func TestJobLogLimit(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	mgr, err := manager.NewJobManager(manager.WithLogDir(dir))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	// The limit includes the headers of the log's frames
+	const logLimit = 1000
+
+	startJob := func(script string, policy manager.LogPolicy) *manager.JobInfo {
+		job, err := mgr.StartJob(
+			context.Background(),
+			"bash",
+			[]string{"-c", script},
+			manager.WithLogLimit(logLimit, policy),
+			manager.WithCgroup(nil),
+			manager.WithCloneFlags(0),
+		)
+		if err != nil {
+			t.Fatalf("Failed starting job: %v", err)
+		}
+		return job
+	}
+
+	t.Run("truncate", func(t *testing.T) {
+		job := startJob("seq 1 1000; echo done >&2", manager.LogTruncate)
+
+		output := streamOutput(t, mgr, job.JobID())
+		if len(output) < logLimit/2 || !strings.HasPrefix(output, "1\n2\n3\n") || strings.Contains(output, "done") {
+			t.Fatalf("expected the first part of the output, received %q", output)
+		}
+
+		waitStopped(t, mgr, job.JobID())
+		if size := logSize(t, dir, job.JobID()); size > logLimit {
+			t.Fatalf("expected log of up to %d bytes, found %d", logLimit, size)
+		}
+		if !job.LogTruncated() || job.TerminationReason() != manager.TerminationExited {
+			t.Fatalf("expected truncated job to exit, received %v/%v", job.LogTruncated(), job.TerminationReason())
+		}
+	})
+
+	t.Run("rotate", func(t *testing.T) {
+		job := startJob("for i in $(seq 1 300); do echo $i; sleep 0.001; done", manager.LogRotate)
+
+		// The stream follows the log through its rotations
+		output := streamOutput(t, mgr, job.JobID())
+		if !strings.HasSuffix(output, "299\n300\n") {
+			t.Fatalf("expected the stream to end with the last lines, received %q", output)
+		}
+
+		waitStopped(t, mgr, job.JobID())
+		if job.LogTruncated() {
+			t.Fatalf("expected rotated log not to be truncated")
+		}
+
+		// Only the latest output is kept, split between two segments
+		output = streamOutput(t, mgr, job.JobID())
+		if len(output) < logLimit/10 || strings.HasPrefix(output, "1\n") || !strings.HasSuffix(output, "299\n300\n") {
+			t.Fatalf("expected the latest output, received %q", output)
+		}
+
+		// Offsets count the output that was dropped
+		var total int64
+		for i := 1; i <= 300; i++ {
+			total += int64(len(fmt.Sprintln(i)))
+		}
+
+		chunks := streamChunks(t, mgr, job.JobID())
+		first, last := chunks[0], chunks[len(chunks)-1]
+		if end := last.Offset + int64(len(last.Data)); first.Offset == 0 || end != total {
+			t.Fatalf("expected offsets up to %d, received %d-%d", total, first.Offset, end)
+		}
+
+		if size := logSize(t, dir, job.JobID()); size > logLimit {
+			t.Fatalf("expected log of up to %d bytes, found %d", logLimit, size)
+		}
+
+		segments, err := filepath.Glob(filepath.Join(dir, job.JobID()+".log*"))
+		if err != nil || len(segments) != 2 {
+			t.Fatalf("expected two log segments, found %v: %v", segments, err)
+		}
+	})
+
+	t.Run("kill", func(t *testing.T) {
+		job := startJob("yes", manager.LogKill)
+
+		waitStopped(t, mgr, job.JobID())
+		if job.TerminationReason() != manager.TerminationLogLimitExceeded {
+			t.Fatalf("expected job to be stopped by its log limit, received %v", job.TerminationReason())
+		}
+
+		if output := streamOutput(t, mgr, job.JobID()); !strings.HasPrefix(output, "y\ny\n") {
+			t.Fatalf("expected the job's output, received %q", output)
+		}
+
+		if size := logSize(t, dir, job.JobID()); size > logLimit {
+			t.Fatalf("expected log of up to %d bytes, found %d", logLimit, size)
+		}
+	})
+}
+
+func TestJobOutputStreams(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager(manager.WithLogDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", "echo out; sleep 0.2; echo err >&2; sleep 0.2; echo out again"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	waitStopped(t, mgr, job.JobID())
+
+	// Both streams are tagged and keep their relative order
+	var received []string
+	for _, chunk := range streamChunks(t, mgr, job.JobID()) {
+		received = append(received, fmt.Sprintf("%v:%s", chunk.Stream, chunk.Data))
+	}
+
+	expected := []string{"Stdout:out\n", "Stderr:err\n", "Stdout:out again\n"}
+	if strings.Join(received, "") != strings.Join(expected, "") {
+		t.Fatalf("expected chunks %q, received %q", expected, received)
+	}
+
+	if output := streamOutput(t, mgr, job.JobID(), manager.WithStreams(manager.OutputStdout)); output != "out\nout again\n" {
+		t.Fatalf("expected stdout only, received %q", output)
+	}
+
+	if output := streamOutput(t, mgr, job.JobID(), manager.WithStreams(manager.OutputStderr)); output != "err\n" {
+		t.Fatalf("expected stderr only, received %q", output)
+	}
+}
+
+func TestJobOutputOrder(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager(manager.WithLogDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	// Each stream is written once, right after the other, so both are
+	// usually pending by the time the manager reads them
+	for _, script := range []string{"echo err >&2; echo out", "echo out; echo err >&2"} {
+		var jobIDs []string
+		for i := 0; i < 20; i++ {
+			job, err := mgr.StartJob(
+				context.Background(),
+				"bash",
+				[]string{"-c", script},
+				manager.WithCgroup(nil),
+				manager.WithCloneFlags(0),
+			)
+			if err != nil {
+				t.Fatalf("Failed starting job: %v", err)
+			}
+			jobIDs = append(jobIDs, job.JobID())
+		}
+
+		expected := "Stderr:err\nStdout:out\n"
+		if strings.HasPrefix(script, "echo out") {
+			expected = "Stdout:out\nStderr:err\n"
+		}
+
+		for _, jobID := range jobIDs {
+			waitStopped(t, mgr, jobID)
+
+			var received string
+			for _, chunk := range streamChunks(t, mgr, jobID) {
+				received += fmt.Sprintf("%v:%s", chunk.Stream, chunk.Data)
+			}
+			if received != expected {
+				t.Fatalf("expected output %q of %q, received %q", expected, script, received)
+			}
+		}
+	}
+}
+
+func TestStreamOptions(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager(manager.WithLogDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", "for i in {1..5}; do echo line$i; sleep 0.1; done"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	waitStopped(t, mgr, job.JobID())
+
+	const output = "line1\nline2\nline3\nline4\nline5\n"
+
+	chunks := streamChunks(t, mgr, job.JobID())
+	var offset int64
+	for _, chunk := range chunks {
+		if chunk.Offset != offset || chunk.Time.IsZero() {
+			t.Fatalf("expected chunk at offset %d with a time, received %+v", offset, chunk)
+		}
+		offset += int64(len(chunk.Data))
+	}
+	if offset != int64(len(output)) {
+		t.Fatalf("expected %d bytes of output, received %d", len(output), offset)
+	}
+
+	tests := []struct {
+		name     string
+		opts     []manager.StreamOption
+		expected string
+	}{
+		{"offset", []manager.StreamOption{manager.WithOffset(8)}, output[8:]},
+		{"offset past the end", []manager.StreamOption{manager.WithOffset(100)}, ""},
+		{"tail", []manager.StreamOption{manager.WithTail(2)}, "line4\nline5\n"},
+		{"tail longer than the output", []manager.StreamOption{manager.WithTail(10)}, output},
+		{"since", []manager.StreamOption{manager.WithSince(chunks[2].Time)}, "line3\nline4\nline5\n"},
+		{"latest position", []manager.StreamOption{manager.WithTail(4), manager.WithOffset(20)}, output[20:]},
+	}
+
+	for _, test := range tests {
+		if received := streamOutput(t, mgr, job.JobID(), test.opts...); received != test.expected {
+			t.Fatalf("%s: expected %q, received %q", test.name, test.expected, received)
+		}
+	}
+
+	if _, err := mgr.StreamJob(context.Background(), job.JobID(), manager.WithOffset(-1)); err == nil {
+		t.Fatalf("expected a negative offset to be rejected")
+	}
+
+	// Without following, the stream of a running job ends with the output
+	// written so far
+	job, err = mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", "echo first; sleep 30"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+	defer mgr.StopJob(job.JobID())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for streamOutput(t, mgr, job.JobID(), manager.WithFollow(false)) != "first\n" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the stream to end with the output written so far")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if job.Status() != manager.JobRunning {
+		t.Fatalf("expected job to be running, received %v", job.Status())
+	}
+}
+
+// openFiles returns the number of files the test process holds open
+func openFiles(t *testing.T) int {
+	t.Helper()
+
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatalf("Failed listing open files: %v", err)
+	}
+
+	return len(fds)
+}
+
+// TestStreamJobCancel isn't parallel, since it counts the goroutines and
+// files of the whole process
+func TestStreamJobCancel(t *testing.T) {
+	mgr, err := manager.NewJobManager(manager.WithLogDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", "seq 1 1000; sleep 100"},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+	defer mgr.StopJob(job.JobID(), manager.WithGracePeriod(0))
+
+	for streamOutput(t, mgr, job.JobID(), manager.WithFollow(false)) == "" {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	goroutines, files := runtime.NumGoroutine(), openFiles(t)
+
+	// Streams of a running job wait for its output until they are cancelled
+	const streams = 5000
+
+	var channels []<-chan manager.OutputChunk
+	var cancels []context.CancelFunc
+	for i := 0; i < streams; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		outputChannel, err := mgr.StreamJob(ctx, job.JobID())
+		if err != nil {
+			t.Fatalf("Failed to stream job: %v", err)
+		}
+		channels = append(channels, outputChannel)
+		cancels = append(cancels, cancel)
+	}
+
+	if n := runtime.NumGoroutine(); n < goroutines+streams {
+		t.Fatalf("expected a goroutine for each stream, found %d", n-goroutines)
+	}
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+
+	// The channels are closed once the streams released their files
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for _, outputChannel := range channels {
+			for range outputChannel {
+			}
+		}
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the channels of cancelled streams to be closed")
+	}
+
+	if n := openFiles(t); n > files {
+		t.Fatalf("expected cancelled streams to close their files, %d are left open", n-files)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > goroutines {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected cancelled streams to exit, %d goroutines are left", runtime.NumGoroutine()-goroutines)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if job.Status() != manager.JobRunning {
+		t.Fatalf("expected job to keep on running, received %v", job.Status())
+	}
+}
+
+func TestStreamBackpressure(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager(manager.WithLogDir(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	// About 250MB of output, far more than a stream buffers
+	const lines = 30000000
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"seq",
+		[]string{strconv.Itoa(lines)},
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	outputChannel, err := mgr.StreamJob(context.Background(), job.JobID())
+	if err != nil {
+		t.Fatalf("Failed to stream job: %v", err)
+	}
+
+	received := sha256.New()
+	var offset int64
+	for chunk := range outputChannel {
+		if chunk.Offset != offset {
+			t.Fatalf("expected chunk at offset %d, received %d", offset, chunk.Offset)
+		}
+		offset += int64(len(chunk.Data))
+		received.Write(chunk.Data)
+
+		// The consumer falls behind while the job is writing
+		if job.Status() == manager.JobRunning {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	expected := sha256.New()
+	var buf []byte
+	for i := int64(1); i <= lines; i++ {
+		buf = append(strconv.AppendInt(buf, i, 10), '\n')
+		if len(buf) > 1<<16 {
+			expected.Write(buf)
+			buf = buf[:0]
+		}
+	}
+	expected.Write(buf)
+
+	if !bytes.Equal(received.Sum(nil), expected.Sum(nil)) {
+		t.Fatalf("expected the job's output to be streamed as is, received %d bytes", offset)
+	}
+
+	if job.Status() != manager.JobStopped || job.ExitCode() != 0 {
+		t.Fatalf("expected job to exit, received %v/%d", job.Status(), job.ExitCode())
+	}
+}
+
+// BenchmarkStreamJob measures the throughput of a job's output streamed to
+// many subscribers at once, and the latency from the time the output was
+// captured to the time it was received
+func BenchmarkStreamJob(b *testing.B) {
+	for _, subscribers := range []int{1, 100, 1000} {
+		b.Run(fmt.Sprintf("subscribers=%d", subscribers), func(b *testing.B) {
+			benchmarkStreamJob(b, subscribers)
+		})
+	}
+}
+
+func benchmarkStreamJob(b *testing.B, subscribers int) {
+	mgr, err := manager.NewJobManager(manager.WithLogDir(b.TempDir()))
+	if err != nil {
+		b.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"cat",
+		nil,
+		manager.WithStdin(true),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		b.Fatalf("Failed starting job: %v", err)
+	}
+
+	stdin, err := mgr.AttachStdin(job.JobID())
+	if err != nil {
+		b.Fatalf("Failed attaching to job: %v", err)
+	}
+
+	block := append(bytes.Repeat([]byte("x"), 4095), '\n')
+	total := int64(b.N) * int64(len(block))
+
+	var latency, chunks atomic.Int64
+	var received, done sync.WaitGroup
+
+	for i := 0; i < subscribers; i++ {
+		outputChannel, err := mgr.StreamJob(context.Background(), job.JobID())
+		if err != nil {
+			b.Fatalf("Failed to stream job: %v", err)
+		}
+
+		received.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+
+			var size int64
+			for chunk := range outputChannel {
+				latency.Add(int64(time.Since(chunk.Time)))
+				chunks.Add(1)
+				if size += int64(len(chunk.Data)); size == total {
+					received.Done()
+				}
+			}
+		}()
+	}
+
+	b.SetBytes(int64(len(block)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := stdin.Write(block); err != nil {
+			b.Fatalf("Failed writing to job: %v", err)
+		}
+	}
+	received.Wait()
+
+	b.StopTimer()
+	b.ReportMetric(float64(latency.Load())/float64(chunks.Load()), "ns-latency")
+
+	if err := stdin.CloseStdin(); err != nil {
+		b.Fatalf("Failed closing stdin: %v", err)
+	}
+	done.Wait()
+}
+
+func TestLogCompression(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	mgr, err := manager.NewJobManager(
+		manager.WithLogDir(dir),
+		manager.WithLogCompression(manager.CompressionGzip),
+	)
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	// The log is rotated, so both of its segments are compressed
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", "for i in $(seq 1 1000); do echo $i; done"},
+		manager.WithLogLimit(2000, manager.LogRotate),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	waitStopped(t, mgr, job.JobID())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.LogCompression() != manager.CompressionGzip {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the log to be compressed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	segments, err := filepath.Glob(filepath.Join(dir, job.JobID()+".log*"))
+	if err != nil || len(segments) != 2 {
+		t.Fatalf("expected two log segments, found %v: %v", segments, err)
+	}
+	for _, segment := range segments {
+		if !strings.HasSuffix(segment, ".gz") {
+			t.Fatalf("expected only compressed segments, found %v", segments)
+		}
+	}
+
+	if job.StoredLogSize() != logSize(t, dir, job.JobID()) || job.StoredLogSize() >= job.LogSize() || job.CompressionRatio() <= 1 {
+		t.Fatalf("expected compressed log, received %d/%d bytes, ratio %f",
+			job.StoredLogSize(), job.LogSize(), job.CompressionRatio())
+	}
+
+	// The compressed log is streamed like before
+	var total int64
+	for i := 1; i <= 1000; i++ {
+		total += int64(len(fmt.Sprintln(i)))
+	}
+
+	chunks := streamChunks(t, mgr, job.JobID())
+	first, last := chunks[0], chunks[len(chunks)-1]
+	if end := last.Offset + int64(len(last.Data)); first.Offset == 0 || end != total {
+		t.Fatalf("expected offsets up to %d, received %d-%d", total, first.Offset, end)
+	}
+
+	if output := streamOutput(t, mgr, job.JobID()); !strings.HasSuffix(output, "999\n1000\n") {
+		t.Fatalf("expected the stream to end with the last lines, received %q", output)
+	}
+
+	if output := streamOutput(t, mgr, job.JobID(), manager.WithTail(2)); output != "999\n1000\n" {
+		t.Fatalf("expected the last lines, received %q", output)
+	}
+
+	if output := streamOutput(t, mgr, job.JobID(), manager.WithOffset(total-4)); output != "000\n" {
+		t.Fatalf("expected the output from the offset, received %q", output)
+	}
+
+	if _, err := mgr.DeleteJob(job.JobID()); err != nil {
+		t.Fatalf("Failed deleting job: %v", err)
+	}
+
+	if size := logSize(t, dir, job.JobID()); size != 0 {
+		t.Fatalf("expected compressed log to be removed, found %d bytes", size)
+	}
+}
+
+func TestListJobs(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	owners := []string{"alice", "bob", "alice", "alice", "bob"}
+	var aliceJobs []string
+
+	for i, owner := range owners {
+		job, err := mgr.StartJob(
+			context.Background(),
+			"true",
+			nil,
+			manager.WithOwner(owner),
+			manager.WithLabels(map[string]string{"index": fmt.Sprint(i)}),
+			manager.WithCgroup(nil),
+			manager.WithCloneFlags(0),
+		)
+		if err != nil {
+			t.Fatalf("Failed starting job: %v", err)
+		}
+		if owner == "alice" {
+			aliceJobs = append(aliceJobs, job.JobID())
+		}
+	}
+
+	// Page through alice's jobs one by one and make sure the order is stable
+	var listed []string
+	pageToken := ""
+	for {
+		jobs, next, err := mgr.ListJobs(&manager.JobFilter{Owner: "alice"}, 1, pageToken)
+		if err != nil {
+			t.Fatalf("Failed listing jobs: %v", err)
+		}
+		for _, job := range jobs {
+			listed = append(listed, job.JobID())
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+
+	if strings.Join(listed, ",") != strings.Join(aliceJobs, ",") {
+		t.Fatalf("expected jobs %v, listed %v", aliceJobs, listed)
+	}
+
+	jobs, _, err := mgr.ListJobs(&manager.JobFilter{
+		Owner:  "bob",
+		Labels: map[string]string{"index": "4"},
+	}, 0, "")
+	if err != nil {
+		t.Fatalf("Failed listing jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Owner() != "bob" {
+		t.Fatalf("expected a single job of bob with index=4, received %d jobs", len(jobs))
+	}
+
+	jobs, _, err = mgr.ListJobs(&manager.JobFilter{
+		Command:      "true",
+		StartedAfter: time.Now().Add(time.Hour),
+	}, 0, "")
+	if err != nil {
+		t.Fatalf("Failed listing jobs: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Fatalf("expected no jobs started in the future, received %d jobs", len(jobs))
+	}
+
+	if _, _, err := mgr.ListJobs(nil, 0, "not-a-token"); err == nil {
+		t.Fatalf("expected an invalid page token to fail")
+	}
+}
+
+func TestJobEnvironment(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	workDir := t.TempDir()
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", `echo "foo=$FOO home=$HOME pwd=$(pwd)"`},
+		manager.WithEnv([]string{"FOO=bar"}),
+		manager.WithWorkingDir(workDir),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	// HOME comes from the default environment rather than the server's
+	expected := fmt.Sprintf("foo=bar home=/ pwd=%s", workDir)
+	if err := checkStreamContains(mgr, job.JobID(), expected); err != nil {
+		t.Fatalf("Check stream failed: %v", err)
+	}
+
+	_, err = mgr.StartJob(context.Background(), "true", nil, manager.WithEnv([]string{"=bar"}))
+	if err == nil {
+		t.Fatalf("expected an invalid environment variable to fail")
+	}
+
+	_, err = mgr.StartJob(context.Background(), "true", nil, manager.WithWorkingDir("relative"))
+	if err == nil {
+		t.Fatalf("expected a relative working directory to fail")
+	}
+}
+
+func TestJobCredential(t *testing.T) {
+	t.Parallel()
+
+	if os.Geteuid() != 0 {
+		t.Skip("running a job as another user requires root")
+	}
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"id",
+		[]string{"-u"},
+		manager.WithCredential(&manager.Credential{UID: 65534, GID: 65534}),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	if err := checkStreamContains(mgr, job.JobID(), "65534"); err != nil {
+		t.Fatalf("Check stream failed: %v", err)
+	}
+}
+
+func TestJobTTY(t *testing.T) {
+	t.Parallel()
+
+	mgr, err := manager.NewJobManager()
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+
+	job, err := mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", `test -t 0 -a -t 1 && echo "tty $(stty size)"`},
+		manager.WithTTY(true, 30, 100),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	if err := checkStreamContains(mgr, job.JobID(), "tty 30 100"); err != nil {
+		t.Fatalf("Check stream failed: %v", err)
+	}
+
+	// The job blocks on the terminal until it is resized and written to
+	job, err = mgr.StartJob(
+		context.Background(),
+		"bash",
+		[]string{"-c", `read line; echo "got $line $(stty size)"`},
+		manager.WithTTY(true, 0, 0),
+		manager.WithCgroup(nil),
+		manager.WithCloneFlags(0),
+	)
+	if err != nil {
+		t.Fatalf("Failed starting job: %v", err)
+	}
+
+	stdin, err := mgr.AttachStdin(job.JobID())
+	if err != nil {
+		t.Fatalf("Failed attaching stdin: %v", err)
+	}
+	defer stdin.Detach()
+
+	if err := stdin.Resize(40, 120); err != nil {
+		t.Fatalf("Failed resizing terminal: %v", err)
+	}
+
+	if _, err := stdin.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Failed writing stdin: %v", err)
+	}
+
+	if err := checkStreamContains(mgr, job.JobID(), "got hello 40 120"); err != nil {
+		t.Fatalf("Check stream failed: %v", err)
+	}
+
+	checkStatus(t, mgr, job.JobID(), manager.JobStopped)
+}
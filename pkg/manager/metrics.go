@@ -0,0 +1,270 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// metricsChannelSize holds a few samples, a client that falls further
+// behind holds back the sampling
+const metricsChannelSize = 8
+
+// JobMetrics is a sample of a running job's resources, with the rates since
+// the previous sample
+type JobMetrics struct {
+	Time time.Time
+	// CPUPercent is relative to a single cpu, a job busy on two cpus is at
+	// 200
+	CPUPercent       float64
+	MemoryCurrent    int64
+	MemoryPeak       int64
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+	ReadIOPS         float64
+	WriteIOPS        float64
+	PidsCurrent      int64
+	// The pressure stall information of the job's cgroup, it is zero if
+	// the kernel doesn't provide it
+	CPUPressure    Pressure
+	MemoryPressure Pressure
+	IOPressure     Pressure
+}
+
+// Pressure is the content of a *.pressure file
+type Pressure struct {
+	// Some is the share of time at least one task was stalled, and Full
+	// the share of time all of the non-idle tasks were stalled at once
+	Some PressureStats
+	Full PressureStats
+}
+
+// PressureStats are the averages in percents over 10, 60 and 300 seconds,
+// and the total time tasks were stalled
+type PressureStats struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  time.Duration
+}
+
+// StreamJobMetrics:
+//   - Samples the job's cgroup every interval, the first sample is sent
+//     once interval passed since the rates need two reads.
+//   - The channel is closed once the job stopped or ctx is done, right
+//     away if the job isn't running.
+//   - Fails for jobs without a cgroup.
+func (m *JobManager) StreamJobMetrics(ctx context.Context, jobID string, interval time.Duration) (<-chan JobMetrics, error) {
+	j, ok := m.jobDB.Load(jobID)
+	if !ok {
+		return nil, fmt.Errorf("job %s was not found in memory", jobID)
+	}
+
+	job, ok := j.(*Job)
+	if !ok {
+		return nil, fmt.Errorf("type assertion failed for job %s", jobID)
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("metrics interval %v must be positive", interval)
+	}
+
+	if job.cgroup == nil {
+		return nil, fmt.Errorf("job %s has no cgroup to sample", jobID)
+	}
+
+	metricsChannel := make(chan JobMetrics, metricsChannelSize)
+	go job.sampleMetrics(ctx, interval, metricsChannel)
+
+	return metricsChannel, nil
+}
+
+// sampleMetrics:
+//   - Runs in a goroutine, sending a sample every interval until the job
+//     stopped or ctx is done.
+//   - The cgroup is deleted once the job stopped, so a read is only used if
+//     the job was still running after it.
+func (j *Job) sampleMetrics(ctx context.Context, interval time.Duration, metricsChannel chan<- JobMetrics) {
+	defer close(metricsChannel)
+
+	// The status channel is closed once the job stopped
+	statusChannel := j.watchStatus(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous *ResourceUsage
+	for {
+		usage, err := j.cgroup.Usage()
+		if err != nil {
+			log.Printf("Failed sampling job %s: %v", j.jobID, err)
+			return
+		}
+
+		cpu, memory, io, err := j.cgroup.Pressures()
+		if err != nil {
+			log.Printf("Failed sampling job %s: %v", j.jobID, err)
+			return
+		}
+
+		if j.Status() != JobRunning {
+			return
+		}
+
+		if previous != nil {
+			metrics := newJobMetrics(previous, usage)
+			metrics.CPUPressure, metrics.MemoryPressure, metrics.IOPressure = cpu, memory, io
+
+			select {
+			case metricsChannel <- metrics:
+			case <-ctx.Done():
+				return
+			}
+		}
+		previous = usage
+
+		if !waitTick(ctx, ticker, statusChannel) {
+			return
+		}
+	}
+}
+
+// waitTick waits for the next tick, it returns false once the job stopped
+// or ctx is done
+func waitTick(ctx context.Context, ticker *time.Ticker, statusChannel <-chan JobStatus) bool {
+	for {
+		select {
+		case <-ticker.C:
+			return true
+		case _, ok := <-statusChannel:
+			if !ok {
+				return false
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// newJobMetrics returns the sample of current, with the rates since
+// previous
+func newJobMetrics(previous, current *ResourceUsage) JobMetrics {
+	metrics := JobMetrics{
+		Time:          current.ReadAt,
+		MemoryCurrent: current.MemoryCurrent,
+		MemoryPeak:    current.MemoryPeak,
+		PidsCurrent:   current.PidsCurrent,
+	}
+
+	elapsed := current.ReadAt.Sub(previous.ReadAt).Seconds()
+	if elapsed <= 0 {
+		return metrics
+	}
+
+	rate := func(previous, current int64) float64 {
+		// Counters only go down if they were reset, e.g a device went away
+		if current < previous {
+			return 0
+		}
+		return float64(current-previous) / elapsed
+	}
+
+	metrics.CPUPercent = rate(int64(previous.CPUUsage), int64(current.CPUUsage)) / float64(time.Second) * 100
+
+	previousIO, currentIO := totalIO(previous.IO), totalIO(current.IO)
+	metrics.ReadBytesPerSec = rate(previousIO.ReadBytes, currentIO.ReadBytes)
+	metrics.WriteBytesPerSec = rate(previousIO.WriteBytes, currentIO.WriteBytes)
+	metrics.ReadIOPS = rate(previousIO.ReadIOs, currentIO.ReadIOs)
+	metrics.WriteIOPS = rate(previousIO.WriteIOs, currentIO.WriteIOs)
+
+	return metrics
+}
+
+// totalIO sums the io of all of the devices
+func totalIO(devices []DeviceIO) DeviceIO {
+	var total DeviceIO
+	for _, device := range devices {
+		total.ReadBytes += device.ReadBytes
+		total.WriteBytes += device.WriteBytes
+		total.ReadIOs += device.ReadIOs
+		total.WriteIOs += device.WriteIOs
+	}
+	return total
+}
+
+// Pressures reads the cpu, memory and io pressure of the cgroup
+func (c *Cgroup) Pressures() (cpu, memory, io Pressure, err error) {
+	if cpu, err = c.readPressure("cpu.pressure"); err != nil {
+		return
+	}
+	if memory, err = c.readPressure("memory.pressure"); err != nil {
+		return
+	}
+	io, err = c.readPressure("io.pressure")
+	return
+}
+
+// readPressure parses a pressure file, which looks like:
+// some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+// full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func (c *Cgroup) readPressure(filename string) (Pressure, error) {
+	var pressure Pressure
+
+	data, err := c.readCgroupFile(filename)
+	// Reading fails with EOPNOTSUPP if psi is disabled
+	if errors.Is(err, unix.EOPNOTSUPP) {
+		return pressure, nil
+	}
+	if err != nil {
+		return pressure, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var stats *PressureStats
+		switch fields[0] {
+		case "some":
+			stats = &pressure.Some
+		case "full":
+			stats = &pressure.Full
+		default:
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return pressure, fmt.Errorf("invalid value %q of %s in %s: %w", value, key, filename, err)
+			}
+
+			switch key {
+			case "avg10":
+				stats.Avg10 = number
+			case "avg60":
+				stats.Avg60 = number
+			case "avg300":
+				stats.Avg300 = number
+			case "total":
+				// total is in microseconds
+				stats.Total = time.Duration(number) * time.Microsecond
+			}
+		}
+	}
+
+	return pressure, nil
+}
@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewJobMetrics(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	previous := &ResourceUsage{
+		ReadAt:   start,
+		CPUUsage: time.Second,
+		IO: []DeviceIO{
+			{Major: 8, ReadBytes: 1000, WriteBytes: 500, ReadIOs: 10, WriteIOs: 4},
+			{Major: 253, Minor: 1, ReadBytes: 2000},
+		},
+	}
+	current := &ResourceUsage{
+		ReadAt:        start.Add(2 * time.Second),
+		CPUUsage:      4 * time.Second,
+		MemoryCurrent: 4096,
+		MemoryPeak:    8192,
+		PidsCurrent:   2,
+		IO: []DeviceIO{
+			{Major: 8, ReadBytes: 3000, WriteBytes: 100, ReadIOs: 20, WriteIOs: 2},
+			{Major: 253, Minor: 1, ReadBytes: 4000},
+		},
+	}
+
+	metrics := newJobMetrics(previous, current)
+
+	// Three seconds of cpu time in two seconds
+	if metrics.CPUPercent != 150 || metrics.MemoryCurrent != 4096 || metrics.MemoryPeak != 8192 || metrics.PidsCurrent != 2 {
+		t.Fatalf("wrong cpu or memory metrics %+v", metrics)
+	}
+
+	// The devices are summed, and counters that went down count as idle
+	if metrics.ReadBytesPerSec != 2000 || metrics.ReadIOPS != 5 || metrics.WriteBytesPerSec != 0 || metrics.WriteIOPS != 0 {
+		t.Fatalf("wrong io metrics %+v", metrics)
+	}
+}
+
+func TestSampleMetrics(t *testing.T) {
+	t.Parallel()
+
+	job, err := NewJob("sleep", nil, withCgroupRoot(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed creating job: %v", err)
+	}
+	if err := job.cgroup.Create(&ResourceLimits{}); err != nil {
+		t.Fatalf("Failed creating cgroup: %v", err)
+	}
+
+	files := map[string]string{
+		"cpu.stat":        "usage_usec 1000\n",
+		"memory.current":  "4096\n",
+		"io.pressure":     "some avg10=1.50 avg60=0.75 avg300=0.10 total=2000\nfull avg10=0.50 avg60=0.25 avg300=0.00 total=1000\n",
+		"memory.pressure": "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(job.cgroup.path, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed writing %s: %v", name, err)
+		}
+	}
+
+	job.status.Store(int32(JobRunning))
+
+	metricsChannel := make(chan JobMetrics, metricsChannelSize)
+	go job.sampleMetrics(context.Background(), 10*time.Millisecond, metricsChannel)
+
+	metrics := <-metricsChannel
+	expectedIO := Pressure{
+		Some: PressureStats{Avg10: 1.5, Avg60: 0.75, Avg300: 0.1, Total: 2 * time.Millisecond},
+		Full: PressureStats{Avg10: 0.5, Avg60: 0.25, Total: time.Millisecond},
+	}
+	if metrics.MemoryCurrent != 4096 || metrics.IOPressure != expectedIO || metrics.CPUPressure != (Pressure{}) {
+		t.Fatalf("wrong sample %+v", metrics)
+	}
+
+	// The samples end once the job stopped
+	job.status.Store(int32(JobStopped))
+	job.notifyStatus(JobStopped)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-metricsChannel:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("expected the samples to end once the job stopped")
+		}
+	}
+}
@@ -0,0 +1,623 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// outputDrainTimeout bounds the time we wait for output after the
+	// process exited, in case a background process still holds it open
+	outputDrainTimeout = time.Second
+	// logRotatedSuffix is appended to the log's path for its older segment
+	logRotatedSuffix = ".1"
+	outputPipePerms  = 0o600
+	// outputReadSize is the size of the reads from the job's fifos
+	outputReadSize = 32 << 10
+)
+
+// outputPipeSuffixes are appended to the log's path for the job's fifos,
+// there is one for each of the job's output streams
+var outputPipeSuffixes = [...]string{
+	OutputStdout: ".pipe",
+	OutputStderr: ".err.pipe",
+}
+
+// LogPolicy decides what happens to a job's output once its log is full
+type LogPolicy int32
+
+const (
+	// LogTruncate drops the output that doesn't fit, the job keeps on running
+	LogTruncate LogPolicy = iota
+	// LogRotate keeps the latest output, the log is split in two segments
+	// of half the limit and the older one is dropped once the newer fills up
+	LogRotate
+	// LogKill stops the job once its log is full
+	LogKill
+)
+
+func (p LogPolicy) String() string {
+	return [...]string{"Truncate", "Rotate", "Kill"}[p]
+}
+
+// WithLogLimit caps the job's output on disk at maxBytes, 0 is unlimited.
+func WithLogLimit(maxBytes int64, policy LogPolicy) JobOption {
+	return func(c *Job) {
+		c.logLimit = maxBytes
+		c.logPolicy = policy
+	}
+}
+
+// LogLimit returns the maximum size of the job's log, 0 if it is unlimited
+func (j *JobInfo) LogLimit() int64 {
+	return j.logLimit
+}
+
+func (j *JobInfo) LogPolicy() LogPolicy {
+	return j.logPolicy
+}
+
+// LogTruncated returns true if some of the job's output was dropped since
+// its log was full, output dropped by rotation doesn't count.
+func (j *JobInfo) LogTruncated() bool {
+	return j.logTruncated.Load()
+}
+
+// logSegments returns the paths of the log's segments, oldest first
+func logSegments(logPath string) []string {
+	return []string{logPath + logRotatedSuffix, logPath}
+}
+
+// logWriter writes the job's output to its log and enforces its limit.
+// Each write becomes a frame tagged with the stream it came from, so the
+// log keeps the order in which the output of both streams was read.
+type logWriter struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	// size is the size of the current segment
+	size int64
+	// offset is the offset of the next output, counting the output
+	// dropped by rotation
+	offset     int64
+	maxBytes   int64
+	policy     LogPolicy
+	credential *Credential
+	// full is called once when output is dropped
+	full     func()
+	fullOnce sync.Once
+	frame    []byte
+	// broadcaster publishes the output once it was written
+	broadcaster *outputBroadcaster
+}
+
+// stream returns a writer for the output of stream
+func (w *logWriter) stream(stream OutputStream) io.Writer {
+	return &streamWriter{log: w, stream: stream}
+}
+
+type streamWriter struct {
+	log    *logWriter
+	stream OutputStream
+}
+
+func (w *streamWriter) Write(data []byte) (int, error) {
+	return w.log.write(w.stream, data)
+}
+
+// write:
+//   - Writes as much of data as fits in the log, rotating it if needed.
+//     Frames are split so each segment only holds whole frames.
+//   - Output that doesn't fit is dropped without an error, so the job's
+//     output keeps on being drained.
+//   - The limit includes the headers of the log and its frames.
+func (w *logWriter) write(stream OutputStream, data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	total := len(data)
+
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > maxFrameSize {
+			chunk = chunk[:maxFrameSize]
+		}
+
+		if w.maxBytes > 0 {
+			room := w.segmentSize() - w.size - frameHeaderSize
+			if room <= 0 {
+				// A new segment wouldn't have more room than this one
+				if w.policy != LogRotate || w.size <= int64(logHeaderSize) {
+					w.fullOnce.Do(w.full)
+					break
+				}
+				if err := w.rotate(); err != nil {
+					return total - len(data), err
+				}
+				continue
+			}
+			if int64(len(chunk)) > room {
+				chunk = chunk[:room]
+			}
+		}
+
+		now := time.Now()
+		w.frame = appendFrame(w.frame[:0], stream, now, chunk)
+		n, err := w.file.Write(w.frame)
+		w.size += int64(n)
+		if err != nil {
+			return total - len(data), err
+		}
+		w.broadcaster.publish(stream, chunk, w.offset, now)
+		w.offset += int64(len(chunk))
+		data = data[len(chunk):]
+	}
+
+	return total, nil
+}
+
+func (w *logWriter) segmentSize() int64 {
+	if w.policy != LogRotate {
+		return w.maxBytes
+	}
+
+	if w.maxBytes < 2 {
+		return 1
+	}
+	return w.maxBytes / 2
+}
+
+// rotate:
+//   - Swaps the current segment with a new empty one, so the log's path
+//     always holds the current segment and readers can reopen it.
+//   - The previous segment replaces the older one, which is dropped.
+func (w *logWriter) rotate() error {
+	tmpPath := w.path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed creating log segment %s: %w", tmpPath, err)
+	}
+
+	if _, err := file.Write(appendLogHeader(nil, w.offset)); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed writing header of log segment %s: %w", tmpPath, err)
+	}
+
+	if w.credential != nil {
+		if err := file.Chown(int(w.credential.UID), int(w.credential.GID)); err != nil {
+			file.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed changing owner of log segment %s: %w", tmpPath, err)
+		}
+	}
+
+	if err := unix.Renameat2(unix.AT_FDCWD, tmpPath, unix.AT_FDCWD, w.path, unix.RENAME_EXCHANGE); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed rotating log %s: %w", w.path, err)
+	}
+
+	if err := os.Rename(tmpPath, w.path+logRotatedSuffix); err != nil {
+		log.Printf("Failed renaming rotated log %s: %v", tmpPath, err)
+	}
+
+	w.file.Close()
+	w.file = file
+	w.size = int64(logHeaderSize)
+
+	return nil
+}
+
+// Close closes the log, the streams that follow it end once they sent
+// the output that was written
+func (w *logWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.broadcaster.close()
+
+	if err := w.file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return fmt.Errorf("failed closing logfile: %w", err)
+	}
+
+	return nil
+}
+
+// logFull is called when the job's output no longer fits in its log
+func (j *Job) logFull() {
+	j.logTruncated.Store(true)
+
+	if j.logPolicy != LogKill {
+		log.Printf("Log of job %s is full, dropping its output", j.jobID)
+		return
+	}
+
+	log.Printf("Log of job %s is full, stopping it", j.jobID)
+
+	if err := j.terminate(); err != nil {
+		log.Printf("Failed stopping job %s: %v", j.jobID, err)
+	}
+}
+
+// outputPipe is the fifo a job writes one of its streams to.  Unlike a
+// pipe, a fifo can be reopened by a new manager to keep on reading the
+// output of an adopted job.
+type outputPipe struct {
+	path string
+	// fd is the non blocking read end of the fifo
+	fd     int
+	writer io.Writer
+	// failed is set once writing to the log failed, the fifo is drained
+	// from then on so the job doesn't block
+	failed bool
+	eof    bool
+}
+
+// openOutputPipe:
+//   - Creates the fifo and opens its read end.
+//   - Returns the end that should be passed to the command.  It is opened
+//     for reading too, so the job never gets a SIGPIPE while no manager is
+//     reading its output, its writes block once the fifo is full instead.
+func openOutputPipe(path string) (*outputPipe, *os.File, error) {
+	if err := unix.Mkfifo(path, outputPipePerms); err != nil {
+		return nil, nil, fmt.Errorf("failed creating fifo %s: %w", path, err)
+	}
+
+	pipe, err := reopenOutputPipe(path)
+	if err != nil {
+		os.Remove(path)
+		return nil, nil, err
+	}
+
+	writer, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		pipe.remove()
+		return nil, nil, fmt.Errorf("failed opening fifo %s: %w", path, err)
+	}
+
+	return pipe, writer, nil
+}
+
+// reopenOutputPipe opens the read end of an existing fifo, it doesn't
+// wait for a writer so it reads an EOF if the job is gone.
+func reopenOutputPipe(path string) (*outputPipe, error) {
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening fifo %s: %w", path, err)
+	}
+
+	return &outputPipe{path: path, fd: fd}, nil
+}
+
+// drain:
+//   - Copies everything the fifo holds to the log, until it is empty or
+//     all of the job's processes closed it.
+//   - Keeps on draining the fifo if the log can't be written, so the job
+//     doesn't block.
+func (p *outputPipe) drain(buf []byte) error {
+	for {
+		n, err := unix.Read(p.fd, buf)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if errors.Is(err, unix.EAGAIN) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading fifo %s: %w", p.path, err)
+		}
+		if n == 0 {
+			p.eof = true
+			return nil
+		}
+
+		if p.failed {
+			continue
+		}
+		if _, err := p.writer.Write(buf[:n]); err != nil {
+			log.Printf("Copying output to %s failed: %v", p.path, err)
+			p.failed = true
+		}
+	}
+}
+
+// remove closes and removes the fifo
+func (p *outputPipe) remove() error {
+	if err := unix.Close(p.fd); err != nil {
+		return fmt.Errorf("failed closing fifo: %w", err)
+	}
+
+	if err := os.Remove(p.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed removing fifo: %w", err)
+	}
+
+	return nil
+}
+
+// outputCopier copies the fifos of all of a job's streams to its log from
+// a single goroutine.  The fifos are watched by an edge triggered epoll,
+// which reports them in the order they became readable, so output is
+// logged in the order it was written unless the job writes to another
+// stream while one is being read.
+type outputCopier struct {
+	pipes []*outputPipe
+	// watched holds the fifos that weren't closed yet by their fd
+	watched map[int32]*outputPipe
+	buf     []byte
+	epollFd int
+	// wakeFd is an eventfd which stops the copy once it is written
+	wakeFd int
+	done   chan struct{}
+	// closeOnce makes closing safe to repeat, the descriptors may have been
+	// reused once they were closed
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// newOutputCopier:
+//   - Watches the fifos and starts copying them, the copier owns the fifos
+//     from then on even if it fails.
+//   - Whatever the fifos held before they were watched is copied right
+//     away, including the EOF of an adopted job that is already gone.
+func newOutputCopier(pipes []*outputPipe) (*outputCopier, error) {
+	c := &outputCopier{
+		pipes:   pipes,
+		watched: make(map[int32]*outputPipe, len(pipes)),
+		buf:     make([]byte, outputReadSize),
+		epollFd: -1,
+		wakeFd:  -1,
+		done:    make(chan struct{}),
+	}
+
+	var err error
+	if c.epollFd, err = unix.EpollCreate1(unix.EPOLL_CLOEXEC); err != nil {
+		c.release()
+		return nil, fmt.Errorf("failed creating epoll: %w", err)
+	}
+
+	if c.wakeFd, err = unix.Eventfd(0, unix.EFD_CLOEXEC|unix.EFD_NONBLOCK); err != nil {
+		c.release()
+		return nil, fmt.Errorf("failed creating eventfd: %w", err)
+	}
+
+	event := unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(c.wakeFd)}
+	if err := unix.EpollCtl(c.epollFd, unix.EPOLL_CTL_ADD, c.wakeFd, &event); err != nil {
+		c.release()
+		return nil, fmt.Errorf("failed watching eventfd: %w", err)
+	}
+
+	for _, pipe := range pipes {
+		event := unix.EpollEvent{Events: unix.EPOLLIN | unix.EPOLLET, Fd: int32(pipe.fd)}
+		if err := unix.EpollCtl(c.epollFd, unix.EPOLL_CTL_ADD, pipe.fd, &event); err != nil {
+			c.release()
+			return nil, fmt.Errorf("failed watching fifo %s: %w", pipe.path, err)
+		}
+		c.watched[int32(pipe.fd)] = pipe
+	}
+
+	for _, pipe := range pipes {
+		c.drain(pipe)
+	}
+
+	go c.copyOutput()
+
+	return c, nil
+}
+
+// copyOutput:
+//   - Runs in a goroutine.
+//   - Copies the fifos to the log in the order epoll reports them, until
+//     all of the job's processes closed them or the copier is closed.
+func (c *outputCopier) copyOutput() {
+	defer close(c.done)
+
+	events := make([]unix.EpollEvent, len(c.pipes)+1)
+	for len(c.watched) > 0 {
+		n, err := unix.EpollWait(c.epollFd, events, -1)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Waiting for output failed: %v", err)
+			return
+		}
+
+		for _, event := range events[:n] {
+			if event.Fd == int32(c.wakeFd) {
+				return
+			}
+			if pipe, ok := c.watched[event.Fd]; ok {
+				c.drain(pipe)
+			}
+		}
+	}
+}
+
+// drain copies what the fifo holds, and stops watching it once all of the
+// job's processes closed it
+func (c *outputCopier) drain(pipe *outputPipe) {
+	if err := pipe.drain(c.buf); err != nil {
+		log.Printf("%v", err)
+		pipe.eof = true
+	}
+
+	if !pipe.eof {
+		return
+	}
+
+	delete(c.watched, int32(pipe.fd))
+	if err := unix.EpollCtl(c.epollFd, unix.EPOLL_CTL_DEL, pipe.fd, nil); err != nil {
+		log.Printf("Failed unwatching fifo %s: %v", pipe.path, err)
+	}
+}
+
+// close waits for the remaining output to be copied, then closes and
+// removes the fifos
+func (c *outputCopier) close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.stop()
+	})
+
+	return c.closeErr
+}
+
+func (c *outputCopier) stop() error {
+	select {
+	case <-c.done:
+	case <-time.After(outputDrainTimeout):
+		log.Printf("Output of %s is still held open, closing it", c.pipes[0].path)
+	}
+
+	// The copy ends without reading what's left, once it is woken up
+	if _, err := unix.Write(c.wakeFd, []byte{1, 0, 0, 0, 0, 0, 0, 0}); err != nil {
+		log.Printf("Failed waking the output copy up: %v", err)
+	}
+	<-c.done
+
+	return c.release()
+}
+
+// release closes the copier's descriptors and removes its fifos
+func (c *outputCopier) release() error {
+	for _, fd := range []int{c.epollFd, c.wakeFd} {
+		if fd >= 0 {
+			unix.Close(fd)
+		}
+	}
+
+	var err error
+	for _, pipe := range c.pipes {
+		if removeErr := pipe.remove(); removeErr != nil && err == nil {
+			err = removeErr
+		}
+	}
+
+	return err
+}
+
+// openOutput:
+//   - Opens the fifos the job writes its stdout and stderr to, unless the
+//     job has a terminal which already copies its output.
+//   - Starts copying them to the log.
+//   - Returns the ends which should be passed to the command.
+func (j *Job) openOutput() (stdout, stderr *os.File, err error) {
+	if j.tty {
+		return nil, nil, nil
+	}
+
+	var pipes []*outputPipe
+	var writers []*os.File
+	for stream, suffix := range outputPipeSuffixes {
+		pipe, writer, err := openOutputPipe(j.logPath + suffix)
+		if err != nil {
+			for i := range pipes {
+				pipes[i].remove()
+				writers[i].Close()
+			}
+			return nil, nil, err
+		}
+		pipe.writer = j.output.stream(OutputStream(stream))
+
+		pipes = append(pipes, pipe)
+		writers = append(writers, writer)
+	}
+
+	if j.outputCopier, err = newOutputCopier(pipes); err != nil {
+		for _, writer := range writers {
+			writer.Close()
+		}
+		return nil, nil, err
+	}
+
+	return writers[OutputStdout], writers[OutputStderr], nil
+}
+
+// reopenOutput resumes copying the output of an adopted job to its log, a
+// terminal can't be reopened since it went away with the previous manager.
+func (j *Job) reopenOutput() error {
+	if j.tty || j.logPath == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(j.logPath, os.O_RDWR|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("failed opening logfile %s: %w", j.logPath, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed reading logfile %s: %w", j.logPath, err)
+	}
+
+	offset, err := logEnd(file)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed reading logfile %s: %w", j.logPath, err)
+	}
+
+	// New segments belong to the log's owner, like the job's first one
+	var credential *Credential
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		credential = &Credential{UID: stat.Uid, GID: stat.Gid}
+	}
+
+	j.output = &logWriter{
+		path:        j.logPath,
+		file:        file,
+		size:        info.Size(),
+		offset:      offset,
+		maxBytes:    j.logLimit,
+		policy:      j.logPolicy,
+		credential:  credential,
+		full:        j.logFull,
+		broadcaster: newOutputBroadcaster(offset),
+	}
+
+	var pipes []*outputPipe
+	for stream, suffix := range outputPipeSuffixes {
+		pipe, err := reopenOutputPipe(j.logPath + suffix)
+		if err != nil {
+			for _, pipe := range pipes {
+				pipe.remove()
+			}
+			return err
+		}
+		pipe.writer = j.output.stream(OutputStream(stream))
+		pipes = append(pipes, pipe)
+	}
+
+	j.outputCopier, err = newOutputCopier(pipes)
+	return err
+}
+
+// removeOutputPipes removes the fifos of a job that nobody reads anymore
+func (j *Job) removeOutputPipes() {
+	for _, suffix := range outputPipeSuffixes {
+		os.Remove(j.logPath + suffix)
+	}
+}
+
+// closeOutput makes sure all of the job's output reached the log
+func (j *Job) closeOutput() error {
+	if j.pty != nil {
+		if err := j.pty.close(); err != nil {
+			return err
+		}
+	}
+
+	if j.outputCopier != nil {
+		return j.outputCopier.close()
+	}
+
+	return nil
+}
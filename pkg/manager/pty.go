@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ptmxPath = "/dev/ptmx"
+	// ptyEOF is the terminal's VEOF character (Ctrl-D), a terminal has no
+	// write end that can be closed so this is how an EOF is sent to the job
+	ptyEOF = 0x04
+)
+
+// WithTTY executes the job with a pseudo terminal as its stdin, stdout and
+// stderr, with an initial window size of rows x cols (0 keeps the default).
+func WithTTY(tty bool, rows, cols uint16) JobOption {
+	return func(c *Job) {
+		c.tty = tty
+		c.ttyRows = rows
+		c.ttyCols = cols
+	}
+}
+
+// pty is the master side of the job's pseudo terminal
+type pty struct {
+	// fd is kept for ioctls, calling master.Fd() would make it blocking
+	fd     int
+	master *os.File
+	done   chan struct{}
+}
+
+// openPty:
+//   - Opens a new master from /dev/ptmx, non blocking so that closing it
+//     interrupts a pending read.
+//   - Unlocks and opens the matching slave /dev/pts/N.
+func openPty() (*pty, *os.File, error) {
+	fd, err := unix.Open(ptmxPath, unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed opening %s: %w", ptmxPath, err)
+	}
+
+	master := os.NewFile(uintptr(fd), ptmxPath)
+
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("failed unlocking pty: %w", err)
+	}
+
+	num, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("failed getting pty number: %w", err)
+	}
+
+	slavePath := fmt.Sprintf("/dev/pts/%d", num)
+	slave, err := os.OpenFile(slavePath, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("failed opening %s: %w", slavePath, err)
+	}
+
+	return &pty{fd: fd, master: master, done: make(chan struct{})}, slave, nil
+}
+
+// resize sets the terminal's window size, the kernel sends a SIGWINCH to
+// the job's foreground process group.
+func (p *pty) resize(rows, cols uint16) error {
+	ws := &unix.Winsize{Row: rows, Col: cols}
+	if err := unix.IoctlSetWinsize(p.fd, unix.TIOCSWINSZ, ws); err != nil {
+		return fmt.Errorf("failed setting window size: %w", err)
+	}
+
+	return nil
+}
+
+// copyOutput:
+//   - Runs in a goroutine.
+//   - Copies everything the job writes to its terminal to the log file.
+//   - Stops once the slave side is closed by all processes (EIO) or the
+//     master is closed.
+func (p *pty) copyOutput(logFile io.Writer) {
+	defer close(p.done)
+
+	_, err := io.Copy(logFile, p.master)
+	if err != nil && !errors.Is(err, unix.EIO) && !errors.Is(err, os.ErrClosed) {
+		log.Printf("Copying terminal output failed: %v", err)
+	}
+}
+
+// close waits for the remaining output to be copied and closes the master
+func (p *pty) close() error {
+	select {
+	case <-p.done:
+	case <-time.After(outputDrainTimeout):
+		log.Printf("Terminal is still held open, closing it")
+	}
+
+	if err := p.master.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return fmt.Errorf("failed closing pty: %w", err)
+	}
+
+	<-p.done
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+)
+
+const defaultRetentionInterval = time.Minute
+
+// RetentionPolicy bounds the finished jobs the manager keeps around, zero
+// fields are unlimited. Jobs that finished first are deleted first.
+type RetentionPolicy struct {
+	// MaxAge is how long a job is kept after it finished
+	MaxAge time.Duration
+	// MaxJobsPerOwner is the number of finished jobs kept for each owner
+	MaxJobsPerOwner int
+	// MaxLogBytes bounds the size of all the jobs' logs, only finished
+	// jobs are deleted to make room
+	MaxLogBytes int64
+	// Interval is how often the policy is enforced, every minute by default
+	Interval time.Duration
+}
+
+func (p RetentionPolicy) enabled() bool {
+	return p.MaxAge > 0 || p.MaxJobsPerOwner > 0 || p.MaxLogBytes > 0
+}
+
+// WithRetentionPolicy deletes finished jobs in the background according to
+// policy.
+func WithRetentionPolicy(policy RetentionPolicy) ManagerOption {
+	return func(m *JobManager) {
+		m.retention = policy
+	}
+}
+
+// WithDeleteHook calls hook for every job that is deleted, whether it was
+// deleted explicitly or by the retention policy.
+func WithDeleteHook(hook func(*JobInfo)) ManagerOption {
+	return func(m *JobManager) {
+		m.deleteHook = hook
+	}
+}
+
+// DeleteJob:
+//   - Loads the job by jobID
+//   - Removes a stopped job along with its log, running jobs must be
+//     stopped first
+func (m *JobManager) DeleteJob(jobID string) (*JobInfo, error) {
+	j, ok := m.jobDB.Load(jobID)
+	if !ok {
+		return nil, fmt.Errorf("job %s was not found in memory", jobID)
+	}
+
+	job, ok := j.(*Job)
+	if !ok {
+		return nil, fmt.Errorf("type assertion failed for job %s", jobID)
+	}
+
+	if err := m.deleteJob(job); err != nil {
+		return nil, err
+	}
+
+	return job.JobInfo, nil
+}
+
+// PruneJobs deletes the finished jobs that match filter and finished
+// before finishedBefore, or all of them if it is zero.
+func (m *JobManager) PruneJobs(filter *JobFilter, finishedBefore time.Time) []*JobInfo {
+	var deleted []*JobInfo
+
+	for _, job := range m.finishedJobs() {
+		if !filter.matches(job.JobInfo) {
+			continue
+		}
+
+		if !finishedBefore.IsZero() && !job.FinishedAt().Before(finishedBefore) {
+			continue
+		}
+
+		if err := m.deleteJob(job); err != nil {
+			log.Printf("Failed pruning job %s: %v", job.jobID, err)
+			continue
+		}
+
+		deleted = append(deleted, job.JobInfo)
+	}
+
+	return deleted
+}
+
+// deleteJob:
+//   - Removes the job from our db, so it is only deleted once.
+//   - Removes the job's log and its record in the store, and lets the hook
+//     know about it.
+//   - A stopped job is persisted once it is cleaned up, which may happen
+//     after it was deleted, so it is marked as deleted to never be saved
+//     again.
+func (m *JobManager) deleteJob(job *Job) error {
+	if !job.Status().isTerminal() {
+		return fmt.Errorf("job %s is %v, only stopped jobs may be deleted", job.jobID, job.Status())
+	}
+
+	if !m.jobDB.CompareAndDelete(job.jobID, job) {
+		return fmt.Errorf("job %s was already deleted", job.jobID)
+	}
+
+	if job.logPath != "" {
+		job.logMu.Lock()
+		for _, path := range logFiles(job.logPath) {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Printf("Failed removing log of job %s: %v", job.jobID, err)
+			}
+		}
+		job.logMu.Unlock()
+	}
+
+	job.storeMu.Lock()
+	job.deleted = true
+	if m.store != nil {
+		if err := m.store.Delete(job.jobID); err != nil {
+			log.Printf("Failed deleting job %s from store: %v", job.jobID, err)
+		}
+	}
+	job.storeMu.Unlock()
+
+	if m.deleteHook != nil {
+		m.deleteHook(job.JobInfo)
+	}
+
+	log.Printf("Deleted job %s", job.jobID)
+
+	return nil
+}
+
+// finishedJobs returns the jobs that reached a terminal status, the ones
+// that finished first come first
+func (m *JobManager) finishedJobs() []*Job {
+	var jobs []*Job
+
+	m.jobDB.Range(func(_, value any) bool {
+		if job, ok := value.(*Job); ok && job.Status().isTerminal() {
+			jobs = append(jobs, job)
+		}
+		return true
+	})
+
+	sort.Slice(jobs, func(i, k int) bool {
+		if !jobs[i].FinishedAt().Equal(jobs[k].FinishedAt()) {
+			return jobs[i].FinishedAt().Before(jobs[k].FinishedAt())
+		}
+		return jobs[i].jobID < jobs[k].jobID
+	})
+
+	return jobs
+}
+
+// reapJobs:
+//   - Runs in a goroutine until the manager is closed.
+//   - Enforces the retention policy right away, which takes care of jobs
+//     restored from the store, and then on every interval.
+func (m *JobManager) reapJobs() {
+	interval := m.retention.Interval
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if deleted := m.enforceRetention(time.Now()); len(deleted) > 0 {
+			log.Printf("Retention policy deleted %d jobs", len(deleted))
+		}
+
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enforceRetention:
+//   - Deletes finished jobs, oldest first, while any of the policy's
+//     bounds is exceeded.
+//   - Logs of running jobs count towards MaxLogBytes, but they are never
+//     deleted.
+func (m *JobManager) enforceRetention(now time.Time) []*JobInfo {
+	policy := m.retention
+
+	var logBytes int64
+	if policy.MaxLogBytes > 0 {
+		m.jobDB.Range(func(_, value any) bool {
+			if job, ok := value.(*Job); ok {
+				logBytes += job.logSize()
+			}
+			return true
+		})
+	}
+
+	finished := m.finishedJobs()
+
+	perOwner := make(map[string]int)
+	for _, job := range finished {
+		perOwner[job.owner]++
+	}
+
+	var deleted []*JobInfo
+	for _, job := range finished {
+		expired := policy.MaxAge > 0 && job.FinishedAt().Before(now.Add(-policy.MaxAge))
+		tooMany := policy.MaxJobsPerOwner > 0 && perOwner[job.owner] > policy.MaxJobsPerOwner
+		tooLarge := policy.MaxLogBytes > 0 && logBytes > policy.MaxLogBytes
+		if !expired && !tooMany && !tooLarge {
+			continue
+		}
+
+		size := job.logSize()
+		if err := m.deleteJob(job); err != nil {
+			log.Printf("Failed deleting job %s: %v", job.jobID, err)
+			continue
+		}
+
+		perOwner[job.owner]--
+		logBytes -= size
+		deleted = append(deleted, job.JobInfo)
+	}
+
+	return deleted
+}
+
+// logSize returns the size of the job's log segments on disk, compressed
+// or not
+func (j *Job) logSize() int64 {
+	if j.logPath == "" {
+		return 0
+	}
+
+	var size int64
+	for _, path := range logFiles(j.logPath) {
+		if info, err := os.Stat(path); err == nil {
+			size += info.Size()
+		}
+	}
+
+	return size
+}
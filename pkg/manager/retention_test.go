@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDeleteBeforePersist(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewJournalStore(filepath.Join(t.TempDir(), "jobs.journal"))
+	if err != nil {
+		t.Fatalf("Failed creating store: %v", err)
+	}
+	defer store.Close()
+
+	mgr, err := NewJobManager(WithLogDir(t.TempDir()), WithJobStore(store))
+	if err != nil {
+		t.Fatalf("Failed creating manager: %v", err)
+	}
+	defer mgr.Close()
+
+	job, err := NewJob("true", nil, WithCgroup(nil))
+	if err != nil {
+		t.Fatalf("Failed creating job: %v", err)
+	}
+	job.store = store
+
+	// The job stopped, but its final status wasn't saved yet
+	job.status.Store(int32(JobStopped))
+	mgr.jobDB.Store(job.jobID, job)
+
+	if err := mgr.deleteJob(job); err != nil {
+		t.Fatalf("Failed deleting job: %v", err)
+	}
+	job.persist()
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed loading store: %v", err)
+	}
+
+	if len(records) != 0 {
+		t.Fatalf("expected the deleted job not to be restored, found %v", records[0])
+	}
+}
@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithStdin connects a pipe to the job's stdin so that data can be written
+// to it with AttachStdin, otherwise the job reads from /dev/null.
+func WithStdin(open bool) JobOption {
+	return func(c *Job) {
+		c.openStdin = open
+	}
+}
+
+// StdinWriter gives a single attached client write access to a job's
+// stdin.  The job's output can still be streamed by any number of readers.
+type StdinWriter struct {
+	job      *Job
+	detached atomic.Bool
+	// writeMu is held while data is written, so Detach can wait for a
+	// write it interrupted to return
+	writeMu sync.Mutex
+}
+
+// Write forwards data to the job's stdin, it blocks while the job isn't
+// reading until the writer is detached
+func (w *StdinWriter) Write(data []byte) (int, error) {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	if w.detached.Load() {
+		return 0, fmt.Errorf("stdin of job %s is detached", w.job.jobID)
+	}
+
+	return w.file().Write(data)
+}
+
+// file returns the job's terminal, or the write end of its stdin pipe
+func (w *StdinWriter) file() *os.File {
+	if w.job.pty != nil {
+		return w.job.pty.master
+	}
+
+	return w.job.stdinPipe
+}
+
+// CloseStdin closes the job's stdin, the process will read an EOF and
+// no more data can be written by any client.  A terminal cannot be closed
+// without hanging up the job, so an EOF character is sent instead.
+func (w *StdinWriter) CloseStdin() error {
+	if w.job.pty != nil {
+		_, err := w.Write([]byte{ptyEOF})
+		return err
+	}
+
+	if w.detached.Load() {
+		return fmt.Errorf("stdin of job %s is detached", w.job.jobID)
+	}
+
+	return w.job.closeStdin()
+}
+
+// Resize changes the window size of the job's terminal
+func (w *StdinWriter) Resize(rows, cols uint16) error {
+	if w.detached.Load() {
+		return fmt.Errorf("stdin of job %s is detached", w.job.jobID)
+	}
+
+	if w.job.pty == nil {
+		return fmt.Errorf("job %s has no terminal", w.job.jobID)
+	}
+
+	return w.job.pty.resize(rows, cols)
+}
+
+// Detach:
+//   - Interrupts a write that is blocked on a job that doesn't read its
+//     stdin, and waits for it to return.
+//   - Releases stdin so another client may attach to it, stdin itself is
+//     kept open.
+func (w *StdinWriter) Detach() {
+	if !w.detached.CompareAndSwap(false, true) {
+		return
+	}
+
+	// Stdin may already be closed, in which case there is nothing to
+	// interrupt
+	file := w.file()
+	_ = file.SetWriteDeadline(time.Now())
+
+	w.writeMu.Lock()
+	_ = file.SetWriteDeadline(time.Time{})
+	w.writeMu.Unlock()
+
+	w.job.stdinAttached.Store(false)
+}
+
+// openStdinPipe:
+// - Creates the pipe for the job's stdin if it was requested, a job with a
+// terminal uses it as its stdin instead.
+// - Returns the read end which should be passed to the command.
+func (j *Job) openStdinPipe() (*os.File, error) {
+	if !j.openStdin || j.tty {
+		return nil, nil
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating stdin pipe: %w", err)
+	}
+	j.stdinPipe = writer
+
+	return reader, nil
+}
+
+func (j *Job) closeStdin() error {
+	if j.stdinPipe == nil {
+		return nil
+	}
+
+	if err := j.stdinPipe.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return fmt.Errorf("failed closing stdin for job %s: %w", j.jobID, err)
+	}
+
+	return nil
+}
+
+// attachStdin:
+// - Makes sure the job is running with a stdin pipe or a terminal.
+// - Allows only one writer at a time.
+func (j *Job) attachStdin() (*StdinWriter, error) {
+	if j.Status() != JobRunning {
+		return nil, fmt.Errorf("job %s is not running", j.jobID)
+	}
+
+	if j.stdinPipe == nil && j.pty == nil {
+		return nil, fmt.Errorf("job %s was not started with stdin", j.jobID)
+	}
+
+	if !j.stdinAttached.CompareAndSwap(false, true) {
+		return nil, fmt.Errorf("stdin of job %s is already attached", j.jobID)
+	}
+
+	return &StdinWriter{job: j}, nil
+}
@@ -0,0 +1,220 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	defaultStopSignal      = unix.SIGTERM
+	defaultStopGracePeriod = 10 * time.Second
+	// stopPollInterval is how often we check whether the job's remaining
+	// processes exited after the main one did
+	stopPollInterval = 50 * time.Millisecond
+	// killTimeout bounds the wait for killed processes to disappear so
+	// their cgroup can be removed
+	killTimeout = 5 * time.Second
+	procPath    = "/proc"
+)
+
+type stopConfig struct {
+	signal      unix.Signal
+	gracePeriod time.Duration
+	// timedOut marks the job as timed out if it is stopped
+	timedOut bool
+}
+
+type StopOption func(*stopConfig)
+
+// WithStopSignal sets the signal that asks the job to stop, SIGTERM by default.
+func WithStopSignal(sig unix.Signal) StopOption {
+	return func(c *stopConfig) {
+		c.signal = sig
+	}
+}
+
+// WithGracePeriod sets how long the job has to exit after being signaled
+// before all of its processes are killed, a zero period kills it right away.
+func WithGracePeriod(period time.Duration) StopOption {
+	return func(c *stopConfig) {
+		c.gracePeriod = period
+	}
+}
+
+// withTimedOut stops the job because it reached its deadline
+func withTimedOut() StopOption {
+	return func(c *stopConfig) {
+		c.timedOut = true
+	}
+}
+
+// terminate:
+// - Does nothing if the job's main process already exited, even if the
+// job wasn't cleaned up yet.
+// - Sends the stop signal to all of the job's processes.
+// - Starts the grace period, the first stop request sets the deadline and
+// later ones only signal the job again.
+// - Kills the job once the deadline passes if its main process is alive,
+// remaining processes are handled by monitorCommand when it exits.
+func (j *Job) terminate(opts ...StopOption) error {
+	cfg := &stopConfig{
+		signal:      defaultStopSignal,
+		gracePeriod: defaultStopGracePeriod,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Nothing to stop for a job that isn't running
+	if j.Status() != JobRunning {
+		return nil
+	}
+
+	j.stopMu.Lock()
+	select {
+	case <-j.exited:
+		j.stopMu.Unlock()
+		return nil
+	default:
+	}
+
+	if cfg.timedOut {
+		j.timedOut.Store(true)
+	}
+
+	deadline := time.Now().Add(cfg.gracePeriod)
+	if !j.stopDeadline.CompareAndSwap(0, deadline.UnixNano()) {
+		deadline = time.Unix(0, j.stopDeadline.Load())
+	} else {
+		go j.escalateStop(deadline)
+	}
+	j.stopMu.Unlock()
+
+	log.Printf("Stopping job %s with %v, killing it at %v", j.jobID, cfg.signal, deadline)
+
+	if cfg.gracePeriod == 0 {
+		return nil
+	}
+
+	return j.signal(cfg.signal)
+}
+
+// escalateStop kills the job unless its main process exited by the deadline
+func (j *Job) escalateStop(deadline time.Time) {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case <-j.exited:
+		return
+	case <-timer.C:
+	}
+
+	log.Printf("Job %s did not stop in time, killing it", j.jobID)
+
+	if err := j.kill(); err != nil {
+		log.Printf("Failed killing job %s: %v", j.jobID, err)
+	}
+}
+
+// reapProcesses:
+// - Runs after the main process of a stopped job exited.
+// - Gives the job's remaining processes until the deadline to exit and
+// kills them afterwards, so nothing the job started survives it.
+func (j *Job) reapProcesses(deadline time.Time) {
+	for j.hasProcesses() && time.Now().Before(deadline) {
+		time.Sleep(stopPollInterval)
+	}
+
+	if !j.hasProcesses() {
+		return
+	}
+
+	log.Printf("Job %s left processes behind, killing them", j.jobID)
+
+	if err := j.kill(); err != nil {
+		log.Printf("Failed killing job %s: %v", j.jobID, err)
+		return
+	}
+
+	// The cgroup can only be removed once the killed processes are gone
+	timeout := time.Now().Add(killTimeout)
+	for j.hasProcesses() && time.Now().Before(timeout) {
+		time.Sleep(stopPollInterval)
+	}
+}
+
+// signal sends sig to the job's cgroup, or to its process group when it
+// has no cgroup
+func (j *Job) signal(sig unix.Signal) error {
+	if j.cgroup != nil {
+		return j.cgroup.Signal(sig)
+	}
+
+	if err := unix.Kill(-int(j.ProcessID()), sig); err != nil && err != unix.ESRCH {
+		return fmt.Errorf("failed sending %v to job %s: %w", sig, j.jobID, err)
+	}
+
+	return nil
+}
+
+func (j *Job) kill() error {
+	if j.cgroup != nil {
+		return j.cgroup.Kill()
+	}
+
+	return j.signal(unix.SIGKILL)
+}
+
+// hasProcesses returns true while any of the job's processes are alive
+func (j *Job) hasProcesses() bool {
+	if j.cgroup != nil {
+		populated, err := j.cgroup.Populated()
+		if err != nil {
+			log.Printf("Job %s: %v", j.jobID, err)
+			return false
+		}
+		return populated
+	}
+
+	alive, err := processGroupAlive(int(j.ProcessID()))
+	if err != nil {
+		log.Printf("Job %s: %v", j.jobID, err)
+		return false
+	}
+	return alive
+}
+
+// processGroupAlive:
+// - Scans /proc for processes in the pgid process group.
+// - Ignores zombies, a killed process may not have been reaped yet by
+// its new parent.
+func processGroupAlive(pgid int) (bool, error) {
+	entries, err := os.ReadDir(procPath)
+	if err != nil {
+		return false, fmt.Errorf("failed reading %s: %w", procPath, err)
+	}
+
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+
+		// The process may exit while we scan, so errors are skipped
+		fields, err := readProcStat(entry.Name())
+		if err != nil || len(fields) < 3 || fields[0] == "Z" {
+			continue
+		}
+
+		if fields[2] == strconv.Itoa(pgid) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,428 @@
+package manager
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	journalFilePerms = 0o600
+	// journalMaxLineSize bounds a single record, labels and arguments are
+	// the only fields that may grow
+	journalMaxLineSize = 1 << 20
+	// The journal is compacted once it holds more than
+	// journalCompactionRatio lines per live job, and at least
+	// journalCompactionMinLines lines
+	journalCompactionRatio    = 4
+	journalCompactionMinLines = 1000
+)
+
+// JobRecord is the persisted state of a job
+type JobRecord struct {
+	JobID      string            `json:"job_id"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args,omitempty"`
+	Executable string            `json:"executable,omitempty"`
+	Owner      string            `json:"owner,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Limits     ResourceLimits    `json:"limits"`
+	TTY        bool              `json:"tty,omitempty"`
+	LogPath    string            `json:"log_path,omitempty"`
+	LogLimit   int64             `json:"log_limit,omitempty"`
+	LogPolicy  LogPolicy         `json:"log_policy,omitempty"`
+	Status     JobStatus         `json:"status"`
+	Pid        int32             `json:"pid,omitempty"`
+	// ProcessStartTime tells the process apart from a later one with the
+	// same pid, in clock ticks since boot
+	ProcessStartTime uint64            `json:"process_start_time,omitempty"`
+	CgroupPath       string            `json:"cgroup_path,omitempty"`
+	ExitCode         int32             `json:"exit_code"`
+	Reason           TerminationReason `json:"reason,omitempty"`
+	Signal           int32             `json:"signal,omitempty"`
+	ErrorMessage     string            `json:"error_message,omitempty"`
+	TimedOut         bool              `json:"timed_out,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+	StartedAt        time.Time         `json:"started_at"`
+	FinishedAt       time.Time         `json:"finished_at"`
+	Deadline         time.Time         `json:"deadline"`
+	LogTruncated     bool              `json:"log_truncated,omitempty"`
+	LogCompression   LogCompression    `json:"log_compression,omitempty"`
+	LogSize          int64             `json:"log_size,omitempty"`
+	StoredLogSize    int64             `json:"stored_log_size,omitempty"`
+	Usage            *ResourceUsage    `json:"usage,omitempty"`
+	// Deleted marks a job that was removed from the store
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// JobStore persists jobs so they survive restarts of the manager.
+// Save is called on every status transition of a job, Delete once a job is
+// deleted, and Load once when the manager is created.
+type JobStore interface {
+	Save(record *JobRecord) error
+	Delete(jobID string) error
+	Load() ([]*JobRecord, error)
+	Close() error
+}
+
+// managerStore is the store as the manager and its jobs use it, saves
+// and deletes are skipped once the manager closed it
+type managerStore struct {
+	JobStore
+	mu     sync.RWMutex
+	closed bool
+}
+
+func (s *managerStore) Save(record *JobRecord) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil
+	}
+
+	return s.JobStore.Save(record)
+}
+
+func (s *managerStore) Delete(jobID string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil
+	}
+
+	return s.JobStore.Delete(jobID)
+}
+
+func (s *managerStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	return s.JobStore.Close()
+}
+
+// JournalStore is a JobStore backed by an append-only file of json lines,
+// the latest line of a job holds its current state.
+type JournalStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	// lines counts the lines in the journal, and live holds the jobs that
+	// were not deleted, so we know when most lines are stale
+	lines int
+	live  map[string]struct{}
+}
+
+// NewJournalStore:
+// - Opens the journal at path, creating it if needed.
+// - Compacts the journal to a single record per job, so it only grows
+// with the number of jobs rather than the number of transitions.
+func NewJournalStore(path string) (*JournalStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), jobWorkerLogDirPerms); err != nil {
+		return nil, fmt.Errorf("failed creating journal directory for %s: %w", path, err)
+	}
+
+	s := &JournalStore{path: path}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Save:
+// - Appends the record to the journal and syncs it to disk.
+// - Doesn't wait for the disk for scheduled jobs, a job that was scheduled
+// when the manager went away is marked as lost on restore anyway.
+// - Compacts the journal once most of its lines are stale.
+func (s *JournalStore) Save(record *JobRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed marshaling job %s: %w", record.JobID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed writing job %s to journal: %w", record.JobID, err)
+	}
+
+	s.lines++
+	if record.Deleted {
+		delete(s.live, record.JobID)
+	} else {
+		s.live[record.JobID] = struct{}{}
+	}
+
+	if s.lines > journalCompactionMinLines && s.lines > journalCompactionRatio*len(s.live) {
+		return s.compact()
+	}
+
+	if record.Status != JobScheduled {
+		if err := s.file.Sync(); err != nil {
+			return fmt.Errorf("failed syncing journal %s: %w", s.path, err)
+		}
+	}
+
+	return nil
+}
+
+// compact replaces the journal with the latest record of every job that
+// was not deleted, and reopens it for appending.  It is called with mu
+// held, or before the store is used.
+func (s *JournalStore) compact() error {
+	records, err := readJournal(s.path)
+	if err != nil {
+		return err
+	}
+
+	if err := writeJournal(s.path, records); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, journalFilePerms)
+	if err != nil {
+		return fmt.Errorf("failed opening journal %s: %w", s.path, err)
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+
+	s.lines = len(records)
+	s.live = make(map[string]struct{}, len(records))
+	for _, record := range records {
+		s.live[record.JobID] = struct{}{}
+	}
+
+	return nil
+}
+
+// Delete appends a record marking the job as deleted, which drops the job
+// when the journal is replayed
+func (s *JournalStore) Delete(jobID string) error {
+	return s.Save(&JobRecord{JobID: jobID, Deleted: true})
+}
+
+// Load returns the latest record of every job in the journal
+func (s *JournalStore) Load() ([]*JobRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return readJournal(s.path)
+}
+
+func (s *JournalStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// readJournal:
+// - Replays the journal, later records of a job replace earlier ones.
+// - Skips a truncated last line, which is left if we crashed mid-write.
+// - Returns the jobs that were not deleted, in the order they were first
+// recorded.
+func readJournal(path string) ([]*JobRecord, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed opening journal %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var order []string
+	latest := make(map[string]*JobRecord)
+
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, readBufferSize), journalMaxLineSize)
+	for scanner.Scan() {
+		lineNum++
+
+		var record JobRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			log.Printf("Skipping corrupt journal line %s:%d: %v", path, lineNum, err)
+			continue
+		}
+
+		if _, ok := latest[record.JobID]; !ok {
+			order = append(order, record.JobID)
+		}
+		latest[record.JobID] = &record
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading journal %s: %w", path, err)
+	}
+
+	var records []*JobRecord
+	for _, jobID := range order {
+		if record := latest[jobID]; !record.Deleted {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// writeJournal atomically replaces the journal with the given records
+func writeJournal(path string, records []*JobRecord) error {
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, journalFilePerms)
+	if err != nil {
+		return fmt.Errorf("failed creating journal %s: %w", tmpPath, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed writing journal %s: %w", tmpPath, err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed writing journal %s: %w", tmpPath, err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed syncing journal %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed replacing journal %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// record returns a snapshot of the job's state for the store
+func (j *Job) record() *JobRecord {
+	record := &JobRecord{
+		JobID:            j.jobID,
+		Command:          j.command,
+		Args:             j.Args(),
+		Executable:       j.Executable(),
+		Owner:            j.owner,
+		Labels:           j.labels,
+		Limits:           j.limits,
+		TTY:              j.tty,
+		LogPath:          j.logPath,
+		LogLimit:         j.logLimit,
+		LogPolicy:        j.logPolicy,
+		Status:           j.Status(),
+		Pid:              j.ProcessID(),
+		ProcessStartTime: j.procStartTime,
+		ExitCode:         j.ExitCode(),
+		Reason:           j.TerminationReason(),
+		Signal:           int32(j.Signal()),
+		ErrorMessage:     j.ErrorMessage(),
+		TimedOut:         j.TimedOut(),
+		CreatedAt:        j.createdAt,
+		StartedAt:        j.StartedAt(),
+		FinishedAt:       j.FinishedAt(),
+		Deadline:         j.Deadline(),
+		LogTruncated:     j.LogTruncated(),
+		LogCompression:   j.LogCompression(),
+		LogSize:          j.LogSize(),
+		StoredLogSize:    j.StoredLogSize(),
+		Usage:            j.Usage(),
+	}
+
+	if j.cgroup != nil {
+		record.CgroupPath = j.cgroup.path
+	}
+
+	return record
+}
+
+// persist saves the job's current state, if the manager has a store and
+// the job wasn't deleted.  The manager's store skips the save once the
+// manager is closed.
+func (j *Job) persist() {
+	if j.store == nil {
+		return
+	}
+
+	j.storeMu.Lock()
+	defer j.storeMu.Unlock()
+
+	if j.deleted {
+		return
+	}
+
+	if err := j.store.Save(j.record()); err != nil {
+		log.Printf("Failed persisting job %s: %v", j.jobID, err)
+	}
+}
+
+// jobFromRecord:
+// - Recreates a job that was loaded from the store.
+// - The job has no process, so it is considered exited until it is adopted.
+func jobFromRecord(record *JobRecord) *Job {
+	job := &Job{
+		JobInfo: &JobInfo{
+			jobID:         record.JobID,
+			command:       record.Command,
+			args:          record.Args,
+			limits:        record.Limits,
+			owner:         record.Owner,
+			labels:        record.Labels,
+			createdAt:     record.CreatedAt,
+			tty:           record.TTY,
+			procStartTime: record.ProcessStartTime,
+			logLimit:      record.LogLimit,
+			logPolicy:     record.LogPolicy,
+		},
+		logPath: record.LogPath,
+		exited:  make(chan struct{}),
+	}
+	close(job.exited)
+
+	if record.CgroupPath != "" {
+		job.cgroup = NewCgroup(filepath.Dir(record.CgroupPath), filepath.Base(record.CgroupPath))
+	}
+
+	job.status.Store(int32(record.Status))
+	job.pid.Store(record.Pid)
+	job.exitCode.Store(record.ExitCode)
+	job.reason.Store(int32(record.Reason))
+	job.termSignal.Store(record.Signal)
+	job.timedOut.Store(record.TimedOut)
+	job.logTruncated.Store(record.LogTruncated)
+	job.logCompression.Store(int32(record.LogCompression))
+	job.logBytes.Store(record.LogSize)
+	job.storedLogBytes.Store(record.StoredLogSize)
+	job.usage.Store(record.Usage)
+	job.executable.Store(record.Executable)
+	if record.ErrorMessage != "" {
+		job.errorMessage.Store(record.ErrorMessage)
+	}
+
+	if !record.StartedAt.IsZero() {
+		job.startedAt.Store(record.StartedAt.UnixNano())
+	}
+	if !record.FinishedAt.IsZero() {
+		job.finishedAt.Store(record.FinishedAt.UnixNano())
+	}
+	if !record.Deadline.IsZero() {
+		job.deadline.Store(record.Deadline.UnixNano())
+	}
+
+	return job
+}
@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"log"
+	"os"
+	"syscall"
+	"time"
+)
+
+// TerminationReason tells why a job is no longer running
+type TerminationReason int32
+
+const (
+	// TerminationNone is the reason of jobs that did not terminate yet
+	TerminationNone TerminationReason = iota
+	TerminationExited
+	TerminationSignaled
+	TerminationStopped
+	TerminationTimedOut
+	TerminationOOMKilled
+	TerminationFailedToStart
+	// TerminationLost is the reason of jobs that were active while the
+	// manager went away
+	TerminationLost
+	// TerminationLogLimitExceeded is the reason of jobs that were stopped
+	// since their output didn't fit in their log
+	TerminationLogLimitExceeded
+)
+
+func (r TerminationReason) String() string {
+	return [...]string{"None", "Exited", "Signaled", "Stopped", "TimedOut", "OOMKilled", "FailedToStart", "Lost",
+		"LogLimitExceeded"}[r]
+}
+
+func (j *JobInfo) TerminationReason() TerminationReason {
+	return TerminationReason(j.reason.Load())
+}
+
+// Signal returns the signal that terminated the job's process, or 0 if it
+// wasn't terminated by a signal.
+func (j *JobInfo) Signal() syscall.Signal {
+	return syscall.Signal(j.termSignal.Load())
+}
+
+// ErrorMessage returns the reason a job failed to start, if it did.
+func (j *JobInfo) ErrorMessage() string {
+	if msg, ok := j.errorMessage.Load().(string); ok {
+		return msg
+	}
+	return ""
+}
+
+// failStart records why the job failed to start and cleans it up
+func (j *Job) failStart(err error) error {
+	j.finishedAt.Store(time.Now().UnixNano())
+	j.errorMessage.Store(err.Error())
+	j.reason.Store(int32(TerminationFailedToStart))
+
+	if stopErr := j.stop(JobScheduled, JobFailedToStart); stopErr != nil {
+		log.Printf("Job %s: %v", j.jobID, stopErr)
+	}
+
+	return err
+}
+
+// setTerminationReason:
+// - Must be called after the main process exited, but before its cgroup
+// is deleted.
+// - Stopping the job, either by the user, its log limit or its timeout,
+// takes precedence over the way the process ended.
+// - A process killed by SIGKILL while its cgroup recorded an OOM kill is
+// considered OOM killed.
+func (j *Job) setTerminationReason(state *os.ProcessState) {
+	reason := TerminationExited
+
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if ok && status.Signaled() {
+		reason = TerminationSignaled
+		j.termSignal.Store(int32(status.Signal()))
+
+		if status.Signal() == syscall.SIGKILL && j.oomKilled() {
+			reason = TerminationOOMKilled
+		}
+	}
+
+	if j.stopDeadline.Load() != 0 {
+		reason = TerminationStopped
+	}
+
+	if j.logLimitExceeded() {
+		reason = TerminationLogLimitExceeded
+	}
+
+	if j.timedOut.Load() {
+		reason = TerminationTimedOut
+	}
+
+	j.reason.Store(int32(reason))
+}
+
+func (j *Job) oomKilled() bool {
+	if j.cgroup == nil {
+		return false
+	}
+
+	kills, err := j.cgroup.OOMKills()
+	if err != nil {
+		log.Printf("Job %s: %v", j.jobID, err)
+		return false
+	}
+
+	return kills > 0
+}
+
+// logLimitExceeded returns true if the job was stopped by its log limit
+func (j *Job) logLimitExceeded() bool {
+	return j.logPolicy == LogKill && j.LogTruncated()
+}
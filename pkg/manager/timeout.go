@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"log"
+	"time"
+)
+
+// WithTimeout bounds the job's runtime, once it passes the job is stopped
+// as if StopJob was called.  A zero timeout lets the job run forever.
+func WithTimeout(timeout time.Duration) JobOption {
+	return func(c *Job) {
+		c.timeout = timeout
+	}
+}
+
+// Deadline returns the time the job will be stopped at, or a zero time if
+// it has no timeout or never started.
+func (j *JobInfo) Deadline() time.Time {
+	if nsec := j.deadline.Load(); nsec != 0 {
+		return time.Unix(0, nsec)
+	}
+	return time.Time{}
+}
+
+// Remaining returns the time left until the deadline of a running job,
+// or 0 if it has no deadline.
+func (j *JobInfo) Remaining() time.Duration {
+	deadline := j.Deadline()
+	if deadline.IsZero() || j.Status() != JobRunning {
+		return 0
+	}
+
+	if remaining := time.Until(deadline); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// TimedOut returns true if the job was stopped because it hit its deadline
+func (j *JobInfo) TimedOut() bool {
+	return j.timedOut.Load()
+}
+
+// enforceTimeout:
+// - Runs in a goroutine for jobs with a timeout.
+// - Stops the job through the regular stop path once the deadline passes,
+// unless the job exited before it.
+func (j *Job) enforceTimeout(deadline time.Time) {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case <-j.exited:
+		return
+	case <-timer.C:
+	}
+
+	log.Printf("Job %s reached its deadline %v, stopping it", j.jobID, deadline)
+
+	// The job only counts as timed out if it was still running
+	if err := j.terminate(withTimedOut()); err != nil {
+		log.Printf("Failed stopping job %s: %v", j.jobID, err)
+	}
+}
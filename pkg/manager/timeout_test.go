@@ -0,0 +1,24 @@
+package manager
+
+import "testing"
+
+func TestTimeoutAfterExit(t *testing.T) {
+	t.Parallel()
+
+	job, err := NewJob("sleep", nil, WithCgroup(nil))
+	if err != nil {
+		t.Fatalf("Failed creating job: %v", err)
+	}
+
+	// The process exited, but the job wasn't cleaned up yet
+	job.status.Store(int32(JobRunning))
+	close(job.exited)
+
+	if err := job.terminate(withTimedOut()); err != nil {
+		t.Fatalf("Failed stopping job: %v", err)
+	}
+
+	if job.TimedOut() || job.stopDeadline.Load() != 0 {
+		t.Fatalf("expected a job that exited on its own not to time out")
+	}
+}
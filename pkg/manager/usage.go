@@ -0,0 +1,286 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ResourceUsage is what a job's processes used.  It is read from the job's
+// cgroup while the job runs, and snapshotted once it stopped, before the
+// cgroup is deleted.  Counters the kernel doesn't provide are left as zero.
+type ResourceUsage struct {
+	// ReadAt is when the usage was read
+	ReadAt time.Time `json:"read_at"`
+	// CPUUsage, CPUUser, CPUSystem and CPUThrottled are read from cpu.stat
+	CPUUsage         time.Duration `json:"cpu_usage,omitempty"`
+	CPUUser          time.Duration `json:"cpu_user,omitempty"`
+	CPUSystem        time.Duration `json:"cpu_system,omitempty"`
+	CPUThrottled     time.Duration `json:"cpu_throttled,omitempty"`
+	ThrottledPeriods int64         `json:"throttled_periods,omitempty"`
+	// MemoryPeak is only provided since linux 5.19
+	MemoryCurrent int64        `json:"memory_current,omitempty"`
+	MemoryPeak    int64        `json:"memory_peak,omitempty"`
+	MemoryEvents  MemoryEvents `json:"memory_events"`
+	IO            []DeviceIO   `json:"io,omitempty"`
+	PidsCurrent   int64        `json:"pids_current,omitempty"`
+	// Process is the usage of the job's main process and the children it
+	// waited for, it is known once the process exited
+	Process *ProcessUsage `json:"process,omitempty"`
+}
+
+// MemoryEvents counts the events of memory.events
+type MemoryEvents struct {
+	Low     int64 `json:"low,omitempty"`
+	High    int64 `json:"high,omitempty"`
+	Max     int64 `json:"max,omitempty"`
+	OOM     int64 `json:"oom,omitempty"`
+	OOMKill int64 `json:"oom_kill,omitempty"`
+}
+
+// DeviceIO is a line of io.stat, the io of a single device
+type DeviceIO struct {
+	Major      uint32 `json:"major"`
+	Minor      uint32 `json:"minor"`
+	ReadBytes  int64  `json:"read_bytes,omitempty"`
+	WriteBytes int64  `json:"write_bytes,omitempty"`
+	ReadIOs    int64  `json:"read_ios,omitempty"`
+	WriteIOs   int64  `json:"write_ios,omitempty"`
+}
+
+// ProcessUsage is the rusage of a process that exited
+type ProcessUsage struct {
+	UserTime   time.Duration `json:"user_time"`
+	SystemTime time.Duration `json:"system_time"`
+	// MaxRSS is the largest resident set size of the process or one of
+	// the children it waited for, in bytes
+	MaxRSS              int64 `json:"max_rss"`
+	MinorFaults         int64 `json:"minor_faults,omitempty"`
+	MajorFaults         int64 `json:"major_faults,omitempty"`
+	InBlocks            int64 `json:"in_blocks,omitempty"`
+	OutBlocks           int64 `json:"out_blocks,omitempty"`
+	VoluntarySwitches   int64 `json:"voluntary_switches,omitempty"`
+	InvoluntarySwitches int64 `json:"involuntary_switches,omitempty"`
+}
+
+// Usage returns the latest resource usage of the job, nil if it is not
+// known.  It is refreshed by QueryJob while the job runs.
+func (j *JobInfo) Usage() *ResourceUsage {
+	return j.usage.Load()
+}
+
+// Usage reads what the cgroup's processes used so far
+func (c *Cgroup) Usage() (*ResourceUsage, error) {
+	usage := &ResourceUsage{ReadAt: time.Now()}
+
+	cpuStat, err := c.readFlatKeyed("cpu.stat")
+	if err != nil {
+		return nil, err
+	}
+	usage.CPUUsage = time.Duration(cpuStat["usage_usec"]) * time.Microsecond
+	usage.CPUUser = time.Duration(cpuStat["user_usec"]) * time.Microsecond
+	usage.CPUSystem = time.Duration(cpuStat["system_usec"]) * time.Microsecond
+	usage.CPUThrottled = time.Duration(cpuStat["throttled_usec"]) * time.Microsecond
+	usage.ThrottledPeriods = cpuStat["nr_throttled"]
+
+	if usage.MemoryCurrent, err = c.readSingleValue("memory.current"); err != nil {
+		return nil, err
+	}
+	if usage.MemoryPeak, err = c.readSingleValue("memory.peak"); err != nil {
+		return nil, err
+	}
+
+	memoryEvents, err := c.readFlatKeyed("memory.events")
+	if err != nil {
+		return nil, err
+	}
+	usage.MemoryEvents = MemoryEvents{
+		Low:     memoryEvents["low"],
+		High:    memoryEvents["high"],
+		Max:     memoryEvents["max"],
+		OOM:     memoryEvents["oom"],
+		OOMKill: memoryEvents["oom_kill"],
+	}
+
+	if usage.IO, err = c.readIOStat(); err != nil {
+		return nil, err
+	}
+
+	if usage.PidsCurrent, err = c.readSingleValue("pids.current"); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// readCgroupFile returns the content of one of the cgroup's files, or nil
+// if the kernel doesn't provide it
+func (c *Cgroup) readCgroupFile(filename string) ([]byte, error) {
+	path := filepath.Join(c.path, filename)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading %s: %w", path, err)
+	}
+
+	return data, nil
+}
+
+// readSingleValue returns the number held by a file such as memory.current
+func (c *Cgroup) readSingleValue(filename string) (int64, error) {
+	data, err := c.readCgroupFile(filename)
+	if err != nil || data == nil {
+		return 0, err
+	}
+
+	value := strings.TrimSpace(string(data))
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s: %w", value, filename, err)
+	}
+
+	return number, nil
+}
+
+// readFlatKeyed returns the numbers of a flat keyed file such as cpu.stat,
+// which looks like:
+// usage_usec 1234
+// user_usec 1000
+func (c *Cgroup) readFlatKeyed(filename string) (map[string]int64, error) {
+	data, err := c.readCgroupFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]int64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q of %s in %s: %w", fields[1], fields[0], filename, err)
+		}
+		values[fields[0]] = value
+	}
+
+	return values, nil
+}
+
+// readIOStat returns the devices of io.stat, which looks like:
+// 8:0 rbytes=1024 wbytes=4096 rios=1 wios=2 dbytes=0 dios=0
+func (c *Cgroup) readIOStat() ([]DeviceIO, error) {
+	data, err := c.readCgroupFile("io.stat")
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []DeviceIO
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var device DeviceIO
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &device.Major, &device.Minor); err != nil {
+			return nil, fmt.Errorf("invalid device %q in io.stat: %w", fields[0], err)
+		}
+
+		for _, field := range fields[1:] {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q of %s in io.stat: %w", value, key, err)
+			}
+
+			switch key {
+			case "rbytes":
+				device.ReadBytes = number
+			case "wbytes":
+				device.WriteBytes = number
+			case "rios":
+				device.ReadIOs = number
+			case "wios":
+				device.WriteIOs = number
+			}
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// newProcessUsage converts the rusage of a process that exited
+func newProcessUsage(state *os.ProcessState) *ProcessUsage {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || rusage == nil {
+		return nil
+	}
+
+	return &ProcessUsage{
+		UserTime:   time.Duration(rusage.Utime.Nano()),
+		SystemTime: time.Duration(rusage.Stime.Nano()),
+		// ru_maxrss is in kilobytes
+		MaxRSS:              rusage.Maxrss << 10,
+		MinorFaults:         rusage.Minflt,
+		MajorFaults:         rusage.Majflt,
+		InBlocks:            rusage.Inblock,
+		OutBlocks:           rusage.Oublock,
+		VoluntarySwitches:   rusage.Nvcsw,
+		InvoluntarySwitches: rusage.Nivcsw,
+	}
+}
+
+// refreshUsage reads the live usage of a running job from its cgroup.  It
+// doesn't replace the snapshot taken once the job stopped, even if they
+// race.
+func (j *Job) refreshUsage() {
+	if j.cgroup == nil || j.Status() != JobRunning {
+		return
+	}
+
+	previous := j.usage.Load()
+
+	usage, err := j.cgroup.Usage()
+	if err != nil {
+		log.Printf("Failed reading usage of job %s: %v", j.jobID, err)
+		return
+	}
+
+	j.usage.CompareAndSwap(previous, usage)
+}
+
+// snapshotUsage:
+//   - Records the final usage of the job, it must be called once its
+//     processes are gone, but before its cgroup is deleted.
+//   - Adds the rusage of the main process if we waited for it.
+func (j *Job) snapshotUsage() {
+	usage := &ResourceUsage{ReadAt: time.Now()}
+
+	if j.cgroup != nil {
+		cgroupUsage, err := j.cgroup.Usage()
+		if err != nil {
+			log.Printf("Failed reading usage of job %s: %v", j.jobID, err)
+		} else {
+			usage = cgroupUsage
+		}
+	}
+
+	usage.Process = j.processUsage
+	j.usage.Store(usage)
+}
@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"context"
+	"sync"
+)
+
+// statusWatchBuffer fits every transition a job can make, so notifying a
+// watcher never blocks
+const statusWatchBuffer = 8
+
+type statusWatcher struct {
+	ch   chan JobStatus
+	last JobStatus
+	// done is closed once the job terminated
+	done chan struct{}
+}
+
+// statusWatchers fans out a job's status transitions to its watchers
+type statusWatchers struct {
+	mu       sync.Mutex
+	nextID   int
+	watchers map[int]*statusWatcher
+}
+
+// isTerminal returns true for statuses a job never leaves
+func (s JobStatus) isTerminal() bool {
+	return s == JobStopped || s == JobFailedToStart
+}
+
+// watchStatus:
+// - Registers a watcher which first receives the job's current status,
+// and then every transition.
+// - The channel is closed once the job reaches a terminal status or ctx
+// is done.
+func (j *Job) watchStatus(ctx context.Context) <-chan JobStatus {
+	w := &j.watchers
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := j.Status()
+	watcher := &statusWatcher{
+		ch:   make(chan JobStatus, statusWatchBuffer),
+		last: status,
+		done: make(chan struct{}),
+	}
+	watcher.ch <- status
+
+	if status.isTerminal() {
+		close(watcher.ch)
+		return watcher.ch
+	}
+
+	if w.watchers == nil {
+		w.watchers = make(map[int]*statusWatcher)
+	}
+	id := w.nextID
+	w.nextID++
+	w.watchers[id] = watcher
+
+	go func() {
+		select {
+		case <-watcher.done:
+			return
+		case <-ctx.Done():
+		}
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		// The watcher may have been closed while we waited for the lock
+		if _, ok := w.watchers[id]; ok {
+			delete(w.watchers, id)
+			close(watcher.ch)
+		}
+	}()
+
+	return watcher.ch
+}
+
+// notifyStatus:
+// - Must be called after every status transition.
+// - Skips watchers that already received the status when registering.
+// - Closes all watchers once the status is terminal.
+func (j *Job) notifyStatus(status JobStatus) {
+	w := &j.watchers
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for id, watcher := range w.watchers {
+		if watcher.last != status {
+			watcher.last = status
+			watcher.ch <- status
+		}
+
+		if status.isTerminal() {
+			delete(w.watchers, id)
+			close(watcher.ch)
+			close(watcher.done)
+		}
+	}
+}
@@ -1,103 +1,454 @@
 package manager
 
 import (
-	"bufio"
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"sync"
+	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/sys/unix"
 )
 
 const (
 	readBufferSize    = 4 << 10
-	eventChannelSize  = 1 << 10
 	outputChannelSize = 1 << 10
 )
 
+// watchObject streams a job's log, and follows the job's output through its
+// broadcaster if it has one
 type watchObject struct {
-	watchID      string
-	watchFD      int32
-	filePath     string
-	eventChannel chan uint32
-	outChannel   chan []byte
+	watchID string
+	// ctx ends the watch once it is done
+	ctx      context.Context
+	filePath string
+	// file is the file at filePath, which is replaced if it's rotated
+	file *os.File
+	// history holds the files that are read before file, file is nil if
+	// it was compressed and is the last of them
+	history      []*logSegment
+	historyPaths []string
+	// broadcaster publishes the output that follows the log
+	broadcaster *outputBroadcaster
+	// streams holds the streams that are sent, all of them if it is empty
+	streams []OutputStream
+	// offset, tailLines and since tell where the stream starts, the latest
+	// of the positions they point to is used.  offset then follows the
+	// output that was read.
+	offset     int64
+	tailLines  int
+	since      time.Time
+	outChannel chan OutputChunk
 }
 
-type isActiveFunc func() bool
-type cleanupFunc func(*watchObject) error
+// watchOption configures what a watch sends
+type watchOption func(*watchObject)
 
-func newWatchObject(inotifyFd int, filePath string) (*watchObject, error) {
-	mask := unix.IN_OPEN | unix.IN_MODIFY | unix.IN_CLOSE_WRITE
-	watchFd, err := unix.InotifyAddWatch(inotifyFd, filePath, uint32(mask))
-	if err != nil {
-		return nil, fmt.Errorf("failed adding watch for %s: %w", filePath, err)
+// withHistory streams the files at paths before the watched file, e.g the
+// log's rotated segment.  Missing ones are skipped.
+func withHistory(paths ...string) watchOption {
+	return func(o *watchObject) {
+		o.historyPaths = append(o.historyPaths, paths...)
 	}
+}
 
-	watchObj := &watchObject{
-		watchID:      uuid.NewString(),
-		watchFD:      int32(watchFd),
-		filePath:     filePath,
-		outChannel:   make(chan []byte, outputChannelSize),
-		eventChannel: make(chan uint32, eventChannelSize),
+// withBroadcaster follows the output published by broadcaster once the log
+// was read, otherwise the stream ends with the log
+func withBroadcaster(broadcaster *outputBroadcaster) watchOption {
+	return func(o *watchObject) {
+		o.broadcaster = broadcaster
+	}
+}
+
+// withStreams only sends the output of streams
+func withStreams(streams ...OutputStream) watchOption {
+	return func(o *watchObject) {
+		o.streams = append(o.streams, streams...)
 	}
+}
 
-	return watchObj, nil
+// withOffset starts the stream at offset of the output
+func withOffset(offset int64) watchOption {
+	return func(o *watchObject) {
+		o.offset = offset
+	}
 }
 
-func (o *watchObject) startWatching(isActive isActiveFunc, cleanup cleanupFunc) error {
-	// At this point, this watch is already registered in inotify and
-	// should be registered in LogWatcher as well.
-	// This means that when we open the file, an IN_OPEN is immediately
-	// triggered and its event is put on the eventChannel so we do not lose
-	// events.
-	file, err := os.Open(o.filePath)
+// withTail starts the stream at the last lines of the output that was
+// written when the watch was added
+func withTail(lines int) watchOption {
+	return func(o *watchObject) {
+		o.tailLines = lines
+	}
+}
+
+// withSince starts the stream at the output written at or after since
+func withSince(since time.Time) watchOption {
+	return func(o *watchObject) {
+		o.since = since
+	}
+}
+
+// watchLog:
+//   - Opens the log at filePath and the history files right away, so they
+//     are the ones the caller sees even if the log is rotated later.
+//     Compressed files are opened in place of the files they replaced.
+//   - Streams them in a goroutine, followed by the output published by the
+//     broadcaster if there is one.
+//   - Cancelling ctx ends the stream right away, its channel is closed and
+//     its files are released.
+func watchLog(ctx context.Context, filePath string, opts ...watchOption) (<-chan OutputChunk, error) {
+	watchObj := &watchObject{
+		watchID:    uuid.NewString(),
+		ctx:        ctx,
+		filePath:   filePath,
+		outChannel: make(chan OutputChunk, outputChannelSize),
+	}
+
+	for _, opt := range opts {
+		opt(watchObj)
+	}
+
+	for _, path := range watchObj.historyPaths {
+		segment, err := openLogSegment(path)
+		if err != nil {
+			watchObj.closeFiles()
+			return nil, err
+		}
+		if segment != nil {
+			watchObj.history = append(watchObj.history, segment)
+		}
+	}
+
+	current, err := openLogSegment(filePath)
+	if err == nil && current == nil {
+		err = fmt.Errorf("failed opening log file %s: %w", filePath, os.ErrNotExist)
+	}
 	if err != nil {
-		return fmt.Errorf("failed opening log file %s: %w", o.filePath, err)
+		watchObj.closeFiles()
+		return nil, err
+	}
+
+	// A compressed log is complete, so it's read along with the history
+	if current.compressed {
+		watchObj.history = append(watchObj.history, current)
+	} else {
+		watchObj.file = current.file
 	}
-	defer file.Close()
 
-	reader := bufio.NewReader(file)
-	buffer := make([]byte, readBufferSize)
+	log.Printf("Start watch [%s] on %s", watchObj.watchID, filePath)
 
-	var once sync.Once
+	go watchObj.startWatching()
 
-	for range o.eventChannel {
-		if err := o.readToEOF(reader, buffer); err != nil {
-			return fmt.Errorf("readToEOF failed: %w", err)
+	return watchObj.outChannel, nil
+}
+
+func (o *watchObject) closeFiles() {
+	for _, segment := range o.history {
+		segment.file.Close()
+	}
+	o.history = nil
+
+	if o.file != nil {
+		o.file.Close()
+	}
+}
+
+// startWatching:
+//   - Sends the output that is in the log, and then the output published
+//     by the broadcaster until it is closed.
+//   - Output that already left the broadcaster's buffer, e.g since the
+//     stream is slow, is read from the log instead.
+//   - Closes outChannel once it is done, or once its context is done.
+func (o *watchObject) startWatching() {
+	defer close(o.outChannel)
+	defer o.closeFiles()
+
+	if o.tailLines > 0 {
+		if err := o.seekTail(); err != nil {
+			log.Printf("Failed finding the tail of [%s]: %v", o.watchID, err)
 		}
-		if !isActive() {
-			once.Do(func() {
-				cleanup(o)
-			})
+	}
+
+	for _, segment := range o.history {
+		if err := o.readSegment(segment); err != nil {
+			log.Printf("Failed reading history of [%s]: %v", o.watchID, err)
+		}
+	}
+
+	// A compressed log is complete, there's nothing to follow
+	if o.file == nil {
+		log.Printf("Read compressed log for [%s]", o.watchID)
+		return
+	}
+
+	reader := newFrameReader(o.file)
+	if err := o.readToEOF(reader); err != nil {
+		log.Printf("Failed reading log of [%s]: %v", o.watchID, err)
+		return
+	}
+
+	if o.broadcaster == nil {
+		log.Printf("Read log for [%s]", o.watchID)
+		return
+	}
+
+	var chunks []OutputChunk
+	for {
+		var wake <-chan struct{}
+		var ok bool
+
+		chunks, wake, ok = o.broadcaster.read(o.offset, chunks[:0])
+		if !ok {
+			if err := o.catchUp(reader); err != nil {
+				log.Printf("Failed catching up with the output of [%s]: %v", o.watchID, err)
+				return
+			}
+			continue
+		}
+
+		for _, chunk := range chunks {
+			if err := o.send(chunk); err != nil {
+				log.Printf("Stopped watch [%s]: %v", o.watchID, err)
+				return
+			}
+		}
+
+		// More output may have been published while the chunks were sent
+		if len(chunks) > 0 {
+			continue
+		}
+
+		if wake == nil {
+			break
+		}
+
+		select {
+		case <-wake:
+		case <-o.ctx.Done():
+			log.Printf("Stopped watch [%s]: %v", o.watchID, o.ctx.Err())
+			return
 		}
 	}
 
 	log.Printf("Exiting watcher routine for [%s]", o.watchID)
+}
+
+// catchUp reads the output that already left the broadcaster's buffer from
+// the log, and moves on to the log's new segments if it was rotated
+func (o *watchObject) catchUp(reader *frameReader) error {
+	offset := o.offset
+	if err := o.readToEOF(reader); err != nil {
+		return err
+	}
+
+	if o.offset > offset {
+		return nil
+	}
+
+	if !o.rotated() {
+		return fmt.Errorf("output at offset %d is missing from the log", o.offset)
+	}
+
+	// The output that was already read is skipped, and the output that was
+	// rotated away is lost
+	segments := logSegments(o.filePath)
+	if rotated, err := os.Open(segments[0]); err == nil {
+		err := o.readToEOF(newFrameReader(rotated))
+		rotated.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Open(o.filePath)
+	if err != nil {
+		return fmt.Errorf("failed opening log file %s: %w", o.filePath, err)
+	}
+
+	log.Printf("Moving watch ID %s to rotated %s", o.watchID, o.filePath)
+
+	o.file.Close()
+	o.file = file
+	reader.Reset(file)
 
 	return nil
 }
 
-func (o *watchObject) readToEOF(reader *bufio.Reader, buffer []byte) error {
+func (o *watchObject) readSegment(segment *logSegment) error {
+	reader, err := segment.reader()
+	if err != nil {
+		return err
+	}
+
+	return o.readToEOF(newFrameReader(reader))
+}
+
+// readToEOF sends the chunks of reader until its end, and moves offset
+// past them
+func (o *watchObject) readToEOF(reader *frameReader) error {
 	for {
-		n, err := reader.Read(buffer)
-		if n > 0 {
-			sendBuf := make([]byte, n)
-			copy(sendBuf, buffer)
-			select {
-			case o.outChannel <- sendBuf:
-			default:
-				log.Printf("outChannel [%s] full, dropping buffer", o.watchID)
+		chunk, err := reader.ReadChunk()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
 			}
+			return err
+		}
+
+		if err := o.send(chunk); err != nil {
+			return err
 		}
+	}
+
+	// The segment's header tells where its output starts, even if it has none
+	if reader.offset > o.offset {
+		o.offset = reader.offset
+	}
+
+	return nil
+}
+
+// send sends the part of chunk that was requested and moves offset past
+// it.  It blocks until the chunk is received, the output is on disk so a
+// slow reader holds back the reading of the output instead of losing it.
+// It fails once the watch's context is done.
+func (o *watchObject) send(chunk OutputChunk) error {
+	end := chunk.Offset + int64(len(chunk.Data))
+
+	if chunk, ok := o.filter(chunk); ok {
+		select {
+		case o.outChannel <- chunk:
+		case <-o.ctx.Done():
+			return o.ctx.Err()
+		}
+	}
+
+	if end > o.offset {
+		o.offset = end
+	}
+
+	return nil
+}
+
+// filter returns the part of chunk that should be sent, false if none of
+// it should
+func (o *watchObject) filter(chunk OutputChunk) (OutputChunk, bool) {
+	if !o.sends(chunk.Stream) {
+		return chunk, false
+	}
+
+	if end := chunk.Offset + int64(len(chunk.Data)); end <= o.offset {
+		return chunk, false
+	}
+
+	if chunk.Offset < o.offset {
+		chunk.Data = chunk.Data[o.offset-chunk.Offset:]
+		chunk.Offset = o.offset
+	}
+
+	// Logs without frames don't tell when their output was written
+	if !o.since.IsZero() && !chunk.Time.IsZero() && chunk.Time.Before(o.since) {
+		return chunk, false
+	}
+
+	return chunk, true
+}
+
+// seekTail:
+//   - Reads the output that was written so far, to find where its last
+//     tailLines lines of the requested streams start.
+//   - Moves the start of the stream there unless it starts later anyway,
+//     and rewinds the files so the stream reads them again.
+func (o *watchObject) seekTail() error {
+	var starts []int64
+	lineStart := true
+
+	segments := o.history
+	if o.file != nil {
+		segments = append(segments[:len(segments):len(segments)], &logSegment{file: o.file})
+	}
+
+	for _, segment := range segments {
+		segmentReader, err := segment.reader()
 		if err != nil {
+			return err
+		}
+
+		reader := newFrameReader(segmentReader)
+		for {
+			chunk, err := reader.ReadChunk()
 			if errors.Is(err, io.EOF) {
-				return nil
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if !o.sends(chunk.Stream) {
+				continue
+			}
+
+			for i := 0; i < len(chunk.Data); {
+				if lineStart {
+					starts = append(starts, chunk.Offset+int64(i))
+					if len(starts) > o.tailLines {
+						starts = starts[1:]
+					}
+				}
+				next := bytes.IndexByte(chunk.Data[i:], '\n')
+				if next < 0 {
+					lineStart = false
+					break
+				}
+				i += next + 1
+				lineStart = true
 			}
-			return err
 		}
 	}
+
+	// The history is rewound when it's read, the file is read from here
+	if o.file != nil {
+		if _, err := o.file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("failed rewinding log: %w", err)
+		}
+	}
+
+	if len(starts) > 0 && starts[0] > o.offset {
+		o.offset = starts[0]
+	}
+
+	return nil
+}
+
+// sends returns true if the output of stream was requested
+func (o *watchObject) sends(stream OutputStream) bool {
+	if len(o.streams) == 0 {
+		return true
+	}
+
+	for _, s := range o.streams {
+		if s == stream {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rotated returns true if the file at filePath is no longer the one we read
+func (o *watchObject) rotated() bool {
+	current, err := o.file.Stat()
+	if err != nil {
+		return false
+	}
+
+	info, err := os.Stat(o.filePath)
+	if err != nil {
+		return false
+	}
+
+	return !os.SameFile(current, info)
 }
@@ -35,12 +35,25 @@ func (h *authHandler) startJobAllowed(ctx context.Context) (string, error) {
 	return clientName, nil
 }
 
+// listJobsAllowed:
+// - Any authenticated client may list jobs, but only its own
+// - Returns the client name that should be used to filter the jobs
+func (h *authHandler) listJobsAllowed(ctx context.Context) (string, error) {
+	return h.startJobAllowed(ctx)
+}
+
 // registerJobID:
 // - Registers a newly created jobID with its owner
 func (h *authHandler) registerJobID(jobID, owner string) {
 	h.jobClientMap.Store(jobID, owner)
 }
 
+// unregisterJobID:
+// - Forgets a deleted jobID, so its owner can no longer access it
+func (h *authHandler) unregisterJobID(jobID string) {
+	h.jobClientMap.Delete(jobID)
+}
+
 // checkOwnership:
 // - Makes sure a job that is being accessed is owned by the calling client
 func (h *authHandler) checkOwnership(ctx context.Context, jobId string) error {
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"fmt"
+	"jobworker/pkg/manager"
+	"strings"
+)
+
+// logCompressions maps the values of JOBWORKER_SERVER_LOG_COMPRESSION to
+// the algorithm the logs of stopped jobs are compressed with
+var logCompressions = map[string]manager.LogCompression{
+	"":     manager.CompressionNone,
+	"none": manager.CompressionNone,
+	"gzip": manager.CompressionGzip,
+}
+
+// newLogCompression reads the algorithm the logs of stopped jobs are
+// compressed with from the environment, logs aren't compressed by default.
+func newLogCompression() (manager.LogCompression, error) {
+	value := getEnvWithDefault("JOBWORKER_SERVER_LOG_COMPRESSION", "none")
+
+	compression, ok := logCompressions[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return manager.CompressionNone, fmt.Errorf("invalid JOBWORKER_SERVER_LOG_COMPRESSION %q, expected none or gzip", value)
+	}
+
+	return compression, nil
+}
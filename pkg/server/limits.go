@@ -0,0 +1,311 @@
+package server
+
+import (
+	"fmt"
+	pb "jobworker/pkg/api"
+	"jobworker/pkg/manager"
+	"math"
+	"runtime"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	defaultMaxMemoryBytes   = 8 << 30
+	defaultMaxIOBytesPerSec = 1 << 30
+	defaultMaxIOPS          = 100_000
+	defaultMaxLogBytes      = 1 << 30
+)
+
+// limitsConfig holds the maximum resources a single job may request and
+// the resources of jobs that did not request any, it is loaded from the
+// environment when the server is created.
+type limitsConfig struct {
+	maxCPUs          float64
+	maxMemoryBytes   int64
+	maxIOBytesPerSec int64
+	// defaults fill the limits a job did not request, they never exceed
+	// the maximums
+	defaults manager.ResourceLimits
+	// maxIOPS bounds the read and write iops of every device a job limits,
+	// 0 means no maximum
+	maxIOPS int64
+	// defaultTimeout applies to jobs that did not request a timeout and
+	// maxTimeout bounds the requested ones, 0 means no timeout
+	defaultTimeout time.Duration
+	maxTimeout     time.Duration
+	// maxLogBytes bounds the size of a job's log, jobs that did not
+	// request a limit get the maximum
+	maxLogBytes int64
+}
+
+// newLimitsConfig:
+//   - Reads the maximum limits from the environment.
+//   - Falls back to the number of cpus on the host, and the default
+//     maximum memory, io rate and iops.
+//   - Jobs get the manager's default cpus, memory and io rate unless
+//     configured otherwise, capped at the maximums.
+//   - Jobs have no timeout unless a default or maximum is configured.
+//   - Jobs' logs are capped at 1GiB unless configured otherwise.
+func newLimitsConfig() (*limitsConfig, error) {
+	maxCPUs, err := strconv.ParseFloat(
+		getEnvWithDefault("JOBWORKER_SERVER_MAX_CPUS", strconv.Itoa(runtime.NumCPU())), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_CPUS: %w", err)
+	}
+	if !isFinite(maxCPUs) {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_CPUS: %v is not a number of cpus", maxCPUs)
+	}
+
+	maxMemory, err := strconv.ParseInt(
+		getEnvWithDefault("JOBWORKER_SERVER_MAX_MEMORY_BYTES", strconv.Itoa(defaultMaxMemoryBytes)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_MEMORY_BYTES: %w", err)
+	}
+
+	maxIO, err := strconv.ParseInt(
+		getEnvWithDefault("JOBWORKER_SERVER_MAX_IO_BPS", strconv.Itoa(defaultMaxIOBytesPerSec)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_IO_BPS: %w", err)
+	}
+
+	maxIOPS, err := strconv.ParseInt(
+		getEnvWithDefault("JOBWORKER_SERVER_MAX_IOPS", strconv.Itoa(defaultMaxIOPS)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_IOPS: %w", err)
+	}
+
+	defaults := manager.DefaultResourceLimits()
+
+	defaultCPUs, err := strconv.ParseFloat(
+		getEnvWithDefault("JOBWORKER_SERVER_DEFAULT_CPUS", strconv.FormatFloat(defaults.CPUs(), 'f', -1, 64)), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_DEFAULT_CPUS: %w", err)
+	}
+	if !isFinite(defaultCPUs) {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_DEFAULT_CPUS: %v is not a number of cpus", defaultCPUs)
+	}
+
+	defaultMemory, err := strconv.ParseInt(
+		getEnvWithDefault("JOBWORKER_SERVER_DEFAULT_MEMORY_BYTES", strconv.FormatInt(defaults.MemMaxBytes, 10)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_DEFAULT_MEMORY_BYTES: %w", err)
+	}
+
+	defaultIO, err := strconv.ParseInt(
+		getEnvWithDefault("JOBWORKER_SERVER_DEFAULT_IO_BPS", strconv.FormatInt(defaults.IOMaxBytesPerSec, 10)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_DEFAULT_IO_BPS: %w", err)
+	}
+
+	defaultTimeout, err := time.ParseDuration(getEnvWithDefault("JOBWORKER_SERVER_DEFAULT_TIMEOUT", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_DEFAULT_TIMEOUT: %w", err)
+	}
+
+	maxTimeout, err := time.ParseDuration(getEnvWithDefault("JOBWORKER_SERVER_MAX_TIMEOUT", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_TIMEOUT: %w", err)
+	}
+
+	maxLog, err := strconv.ParseInt(
+		getEnvWithDefault("JOBWORKER_SERVER_MAX_LOG_BYTES", strconv.Itoa(defaultMaxLogBytes)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_LOG_BYTES: %w", err)
+	}
+
+	// Without an explicit default, jobs may run up to the maximum
+	if defaultTimeout == 0 || (maxTimeout > 0 && defaultTimeout > maxTimeout) {
+		defaultTimeout = maxTimeout
+	}
+
+	defaults = manager.ResourceLimits{
+		CPUMaxQuotaMicroSec: manager.CPUQuotaForCPUs(math.Min(defaultCPUs, maxCPUs)),
+		MemMaxBytes:         minInt64(defaultMemory, maxMemory),
+		IOMaxBytesPerSec:    minInt64(defaultIO, maxIO),
+	}
+	if err := defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid default limits: %w", err)
+	}
+
+	return &limitsConfig{
+		maxCPUs:          maxCPUs,
+		maxMemoryBytes:   maxMemory,
+		maxIOBytesPerSec: maxIO,
+		defaults:         defaults,
+		maxIOPS:          maxIOPS,
+		defaultTimeout:   defaultTimeout,
+		maxTimeout:       maxTimeout,
+		maxLogBytes:      maxLog,
+	}, nil
+}
+
+// resourceLimits:
+// - Converts the requested limits to manager.ResourceLimits, limits that
+// were not requested get the server's defaults.
+// - Rates an io limit leaves unset get the job's io rate, and unset iops
+// get the maximum, so a device limit can't lift the default limits.
+// - Returns an InvalidArgument if any of the limits exceeds its maximum, or
+// an io limit targets a device that isn't a known block device.
+func (c *limitsConfig) resourceLimits(req *pb.ResourceLimits) (manager.ResourceLimits, error) {
+	if !isFinite(req.GetCpus()) {
+		return manager.ResourceLimits{}, status.Errorf(codes.InvalidArgument, "cpus %v is not a number of cpus", req.GetCpus())
+	}
+
+	limits := manager.ResourceLimits{
+		CPUMaxQuotaMicroSec: manager.CPUQuotaForCPUs(req.GetCpus()),
+		MemMaxBytes:         req.GetMemoryBytes(),
+		IOMaxBytesPerSec:    req.GetIoBytesPerSec(),
+	}
+
+	for _, ioLimit := range req.GetIoLimits() {
+		limits.IOLimits = append(limits.IOLimits, manager.IOLimit{
+			Device:           ioLimit.GetDevice(),
+			ReadBytesPerSec:  ioLimit.GetReadBytesPerSec(),
+			WriteBytesPerSec: ioLimit.GetWriteBytesPerSec(),
+			ReadIOPS:         ioLimit.GetReadIops(),
+			WriteIOPS:        ioLimit.GetWriteIops(),
+		})
+	}
+
+	if err := limits.Validate(); err != nil {
+		return limits, status.Errorf(codes.InvalidArgument, "invalid limits: %v", err)
+	}
+
+	fillUnset(&limits.CPUMaxQuotaMicroSec, c.defaults.CPUMaxQuotaMicroSec)
+	fillUnset(&limits.MemMaxBytes, c.defaults.MemMaxBytes)
+	fillUnset(&limits.IOMaxBytesPerSec, c.defaults.IOMaxBytesPerSec)
+
+	if req.GetCpus() > c.maxCPUs {
+		return limits, status.Errorf(codes.InvalidArgument, "cpus %v exceeds maximum of %v", req.GetCpus(), c.maxCPUs)
+	}
+
+	if limits.MemMaxBytes > c.maxMemoryBytes {
+		return limits, status.Errorf(codes.InvalidArgument,
+			"memory %d exceeds maximum of %d bytes", limits.MemMaxBytes, c.maxMemoryBytes)
+	}
+
+	if limits.IOMaxBytesPerSec > c.maxIOBytesPerSec {
+		return limits, status.Errorf(codes.InvalidArgument,
+			"io rate %d exceeds maximum of %d bytes/sec", limits.IOMaxBytesPerSec, c.maxIOBytesPerSec)
+	}
+
+	for i := range limits.IOLimits {
+		ioLimit := &limits.IOLimits[i]
+		if ioLimit.ReadBytesPerSec > c.maxIOBytesPerSec || ioLimit.WriteBytesPerSec > c.maxIOBytesPerSec {
+			return limits, status.Errorf(codes.InvalidArgument,
+				"io rate of %s exceeds maximum of %d bytes/sec", ioLimit.Device, c.maxIOBytesPerSec)
+		}
+
+		if c.maxIOPS > 0 && (ioLimit.ReadIOPS > c.maxIOPS || ioLimit.WriteIOPS > c.maxIOPS) {
+			return limits, status.Errorf(codes.InvalidArgument,
+				"iops of %s exceed maximum of %d", ioLimit.Device, c.maxIOPS)
+		}
+
+		fillUnset(&ioLimit.ReadBytesPerSec, limits.IOMaxBytesPerSec)
+		fillUnset(&ioLimit.WriteBytesPerSec, limits.IOMaxBytesPerSec)
+		fillUnset(&ioLimit.ReadIOPS, c.maxIOPS)
+		fillUnset(&ioLimit.WriteIOPS, c.maxIOPS)
+	}
+
+	return limits, nil
+}
+
+// fillUnset sets a limit that is 0 to value
+func fillUnset(limit *int64, value int64) {
+	if *limit == 0 {
+		*limit = value
+	}
+}
+
+// isFinite returns false for NaN and infinite cpus, which can't be
+// converted to a cpu quota
+func isFinite(cpus float64) bool {
+	return !math.IsNaN(cpus) && !math.IsInf(cpus, 0)
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// timeout:
+// - Returns the default timeout if the job did not request one.
+// - Returns an InvalidArgument if the timeout is negative or exceeds the maximum.
+func (c *limitsConfig) timeout(req *durationpb.Duration) (time.Duration, error) {
+	if req == nil {
+		return c.defaultTimeout, nil
+	}
+
+	if err := req.CheckValid(); err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid timeout: %v", err)
+	}
+
+	timeout := req.AsDuration()
+	if timeout < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "timeout %v must not be negative", timeout)
+	}
+
+	if timeout == 0 {
+		return c.defaultTimeout, nil
+	}
+
+	if c.maxTimeout > 0 && timeout > c.maxTimeout {
+		return 0, status.Errorf(codes.InvalidArgument, "timeout %v exceeds maximum of %v", timeout, c.maxTimeout)
+	}
+
+	return timeout, nil
+}
+
+// logLimit:
+// - Returns the maximum log size if the job did not request a limit.
+// - Returns an InvalidArgument if the limit is negative, exceeds the maximum
+// or its policy is unknown.
+func (c *limitsConfig) logLimit(req *pb.LogLimit) (int64, manager.LogPolicy, error) {
+	maxBytes := req.GetMaxBytes()
+	if maxBytes < 0 {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "log limit %d must not be negative", maxBytes)
+	}
+
+	if maxBytes == 0 {
+		maxBytes = c.maxLogBytes
+	}
+
+	if c.maxLogBytes > 0 && maxBytes > c.maxLogBytes {
+		return 0, 0, status.Errorf(codes.InvalidArgument,
+			"log limit %d exceeds maximum of %d bytes", maxBytes, c.maxLogBytes)
+	}
+
+	policy, ok := LogPolicyMap[req.GetPolicy()]
+	if !ok {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "unknown log policy %v", req.GetPolicy())
+	}
+
+	return maxBytes, policy, nil
+}
+
+func limitsResponse(limits manager.ResourceLimits) *pb.ResourceLimits {
+	response := &pb.ResourceLimits{
+		Cpus:          limits.CPUs(),
+		MemoryBytes:   limits.MemMaxBytes,
+		IoBytesPerSec: limits.IOMaxBytesPerSec,
+	}
+
+	for _, ioLimit := range limits.IOLimits {
+		response.IoLimits = append(response.IoLimits, &pb.IOLimit{
+			Device:           ioLimit.Device,
+			ReadBytesPerSec:  ioLimit.ReadBytesPerSec,
+			WriteBytesPerSec: ioLimit.WriteBytesPerSec,
+			ReadIops:         ioLimit.ReadIOPS,
+			WriteIops:        ioLimit.WriteIOPS,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	pb "jobworker/pkg/api"
+	"jobworker/pkg/manager"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultMetricsInterval = time.Second
+	// minMetricsInterval keeps clients from turning the sampling of the
+	// cgroup into a busy loop
+	minMetricsInterval = 100 * time.Millisecond
+)
+
+// metricsInterval validates the requested sampling interval, a missing
+// interval is the default one
+func metricsInterval(req *durationpb.Duration) (time.Duration, error) {
+	if req == nil {
+		return defaultMetricsInterval, nil
+	}
+
+	if err := req.CheckValid(); err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid interval: %v", err)
+	}
+
+	interval := req.AsDuration()
+	if interval == 0 {
+		return defaultMetricsInterval, nil
+	}
+
+	if interval < minMetricsInterval {
+		return 0, status.Errorf(codes.InvalidArgument, "interval %v is below the minimum of %v", interval, minMetricsInterval)
+	}
+
+	return interval, nil
+}
+
+func metricsResponse(metrics manager.JobMetrics) *pb.JobMetrics {
+	return &pb.JobMetrics{
+		Time:               timestamppb.New(metrics.Time),
+		CpuPercent:         metrics.CPUPercent,
+		MemoryCurrentBytes: metrics.MemoryCurrent,
+		MemoryPeakBytes:    metrics.MemoryPeak,
+		ReadBytesPerSec:    metrics.ReadBytesPerSec,
+		WriteBytesPerSec:   metrics.WriteBytesPerSec,
+		ReadIops:           metrics.ReadIOPS,
+		WriteIops:          metrics.WriteIOPS,
+		PidsCurrent:        metrics.PidsCurrent,
+		CpuPressure:        pressureResponse(metrics.CPUPressure),
+		MemoryPressure:     pressureResponse(metrics.MemoryPressure),
+		IoPressure:         pressureResponse(metrics.IOPressure),
+	}
+}
+
+func pressureResponse(pressure manager.Pressure) *pb.Pressure {
+	stats := func(stats manager.PressureStats) *pb.PressureStats {
+		return &pb.PressureStats{
+			Avg10:  stats.Avg10,
+			Avg60:  stats.Avg60,
+			Avg300: stats.Avg300,
+			Total:  durationpb.New(stats.Total),
+		}
+	}
+
+	return &pb.Pressure{
+		Some: stats(pressure.Some),
+		Full: stats(pressure.Full),
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"fmt"
+	"jobworker/pkg/manager"
+	"strconv"
+	"time"
+)
+
+// newRetentionPolicy:
+//   - Reads the retention policy of finished jobs from the environment.
+//   - Finished jobs are kept until they are deleted unless a maximum age,
+//     number of jobs per user or total log size is configured.
+func newRetentionPolicy() (manager.RetentionPolicy, error) {
+	maxAge, err := time.ParseDuration(getEnvWithDefault("JOBWORKER_SERVER_RETENTION_MAX_AGE", "0"))
+	if err != nil {
+		return manager.RetentionPolicy{}, fmt.Errorf("invalid JOBWORKER_SERVER_RETENTION_MAX_AGE: %w", err)
+	}
+
+	maxJobs, err := strconv.Atoi(getEnvWithDefault("JOBWORKER_SERVER_RETENTION_MAX_JOBS_PER_USER", "0"))
+	if err != nil {
+		return manager.RetentionPolicy{}, fmt.Errorf("invalid JOBWORKER_SERVER_RETENTION_MAX_JOBS_PER_USER: %w", err)
+	}
+
+	maxLogBytes, err := strconv.ParseInt(getEnvWithDefault("JOBWORKER_SERVER_RETENTION_MAX_LOG_BYTES", "0"), 10, 64)
+	if err != nil {
+		return manager.RetentionPolicy{}, fmt.Errorf("invalid JOBWORKER_SERVER_RETENTION_MAX_LOG_BYTES: %w", err)
+	}
+
+	interval, err := time.ParseDuration(getEnvWithDefault("JOBWORKER_SERVER_RETENTION_INTERVAL", "1m"))
+	if err != nil {
+		return manager.RetentionPolicy{}, fmt.Errorf("invalid JOBWORKER_SERVER_RETENTION_INTERVAL: %w", err)
+	}
+
+	if maxAge < 0 || maxJobs < 0 || maxLogBytes < 0 || interval <= 0 {
+		return manager.RetentionPolicy{}, fmt.Errorf("retention policy must not be negative")
+	}
+
+	return manager.RetentionPolicy{
+		MaxAge:          maxAge,
+		MaxJobsPerOwner: maxJobs,
+		MaxLogBytes:     maxLogBytes,
+		Interval:        interval,
+	}, nil
+}
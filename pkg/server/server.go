@@ -5,15 +5,27 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	pb "jobworker/pkg/api"
 	"jobworker/pkg/manager"
 	"log"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultLogDir       = "/tmp/jobworker"
+	defaultJobStoreName = "jobs.journal"
 )
 
 var (
@@ -24,23 +36,115 @@ var (
 		manager.JobRunning:       pb.JobStatus_jobRunning,
 		manager.JobStopped:       pb.JobStatus_jobStopped,
 	}
+
+	TerminationReasonMap = map[manager.TerminationReason]pb.TerminationReason{
+		manager.TerminationNone:             pb.TerminationReason_terminationNone,
+		manager.TerminationExited:           pb.TerminationReason_terminationExited,
+		manager.TerminationSignaled:         pb.TerminationReason_terminationSignaled,
+		manager.TerminationStopped:          pb.TerminationReason_terminationStopped,
+		manager.TerminationTimedOut:         pb.TerminationReason_terminationTimedOut,
+		manager.TerminationOOMKilled:        pb.TerminationReason_terminationOOMKilled,
+		manager.TerminationFailedToStart:    pb.TerminationReason_terminationFailedToStart,
+		manager.TerminationLost:             pb.TerminationReason_terminationLost,
+		manager.TerminationLogLimitExceeded: pb.TerminationReason_terminationLogLimitExceeded,
+	}
+
+	LogPolicyMap = map[pb.LogPolicy]manager.LogPolicy{
+		pb.LogPolicy_logTruncate: manager.LogTruncate,
+		pb.LogPolicy_logRotate:   manager.LogRotate,
+		pb.LogPolicy_logKill:     manager.LogKill,
+	}
+
+	OutputStreamMap = map[manager.OutputStream]pb.OutputStream{
+		manager.OutputStdout: pb.OutputStream_outputStdout,
+		manager.OutputStderr: pb.OutputStream_outputStderr,
+	}
+
+	LogCompressionMap = map[manager.LogCompression]pb.LogCompression{
+		manager.CompressionNone: pb.LogCompression_compressionNone,
+		manager.CompressionGzip: pb.LogCompression_compressionGzip,
+	}
 )
 
 type JobWorkerServer struct {
 	pb.UnimplementedJobWorkerServer
 	jobManager  *manager.JobManager
 	authHandler *authHandler
+	limits      *limitsConfig
+	users       *userMap
 	grpcServer  *grpc.Server
+	// allowInheritEnv controls whether jobs may inherit the server's environment
+	allowInheritEnv bool
+	// maxStopGracePeriod is the longest grace period a stop request may ask for
+	maxStopGracePeriod time.Duration
 }
 
 func NewJobWorkerServer() (*JobWorkerServer, error) {
-	mgr, err := manager.NewJobManager()
+	limits, err := newLimitsConfig()
 	if err != nil {
+		return nil, fmt.Errorf("failed loading limits configuration: %w", err)
+	}
+
+	users, err := loadUserMap(getEnvWithDefault("JOBWORKER_SERVER_USER_MAP", "config/users.conf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed loading user map: %w", err)
+	}
+
+	maxStopGracePeriod, err := newMaxStopGracePeriod()
+	if err != nil {
+		return nil, err
+	}
+
+	// Jobs get a clean environment unless this is explicitly enabled
+	allowInheritEnv := getEnvWithDefault("JOBWORKER_SERVER_ALLOW_INHERIT_ENV", "") != ""
+
+	retention, err := newRetentionPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	compression, err := newLogCompression()
+	if err != nil {
+		return nil, err
+	}
+
+	// Jobs are kept in a journal next to their logs unless configured otherwise
+	logDir := getEnvWithDefault("JOBWORKER_SERVER_LOG_DIR", defaultLogDir)
+	store, err := manager.NewJournalStore(
+		getEnvWithDefault("JOBWORKER_SERVER_JOB_STORE", filepath.Join(logDir, defaultJobStoreName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed opening job store: %w", err)
+	}
+
+	// Deleted jobs, including the ones deleted by the retention policy,
+	// are no longer accessible
+	authHandler := newAuthHandler()
+	mgr, err := manager.NewJobManager(
+		manager.WithJobStore(store),
+		manager.WithLogDir(logDir),
+		manager.WithRetentionPolicy(retention),
+		manager.WithLogCompression(compression),
+		manager.WithDeleteHook(func(jobInfo *manager.JobInfo) {
+			authHandler.unregisterJobID(jobInfo.JobID())
+		}),
+	)
+	if err != nil {
+		store.Close()
 		return nil, fmt.Errorf("failed creating manager: %w", err)
 	}
+
+	// Owners of restored jobs keep access to them
+	for _, jobInfo := range mgr.Jobs() {
+		authHandler.registerJobID(jobInfo.JobID(), jobInfo.Owner())
+	}
+
 	return &JobWorkerServer{
-		jobManager:  mgr,
-		authHandler: newAuthHandler(),
+		jobManager:         mgr,
+		authHandler:        authHandler,
+		limits:             limits,
+		users:              users,
+		allowInheritEnv:    allowInheritEnv,
+		maxStopGracePeriod: maxStopGracePeriod,
 	}, nil
 }
 
@@ -102,31 +206,87 @@ func (s *JobWorkerServer) Close() {
 	if s.grpcServer != nil {
 		s.grpcServer.Stop()
 	}
+
+	if err := s.jobManager.Close(); err != nil {
+		log.Printf("Failed closing job manager: %v", err)
+	}
 }
 
 // StartJob:
 // - Validates peer certificate
-// - Starts a new job in the manager
+// - Finds the unix user the client's jobs run as
+// - Validates the requested resource limits, timeout, log limit and
+// environment policy
+// - Starts a new job in the manager, a job that failed to start is
+// returned with its termination reason and error message
 func (s *JobWorkerServer) StartJob(ctx context.Context, req *pb.StartJobRequest) (*pb.JobResponse, error) {
 	owner, err := s.authHandler.startJobAllowed(ctx)
 	if err != nil {
 		return &pb.JobResponse{}, err
 	}
 
-	log.Printf("StartJob: %v", req)
+	cred, err := s.users.credential(owner)
+	if err != nil {
+		return &pb.JobResponse{}, err
+	}
+
+	// The request is not logged as a whole since its env may hold secrets
+	log.Printf("StartJob: command=%s args=%v limits=%v", req.Command, req.Arguments, req.Limits)
+
+	limits, err := s.limits.resourceLimits(req.Limits)
+	if err != nil {
+		return &pb.JobResponse{}, err
+	}
+
+	if req.InheritEnv && !s.allowInheritEnv {
+		return &pb.JobResponse{}, status.Errorf(codes.PermissionDenied, "inheriting the server environment is not allowed")
+	}
+
+	timeout, err := s.limits.timeout(req.Timeout)
+	if err != nil {
+		return &pb.JobResponse{}, err
+	}
+
+	rows, cols, err := windowSize(req.WindowSize)
+	if err != nil {
+		return &pb.JobResponse{}, err
+	}
+
+	logLimit, logPolicy, err := s.limits.logLimit(req.LogLimit)
+	if err != nil {
+		return &pb.JobResponse{}, err
+	}
 
-	var jobOpts []manager.JobOption
+	jobOpts := []manager.JobOption{
+		manager.WithResourceLimits(limits),
+		manager.WithOwner(owner),
+		manager.WithCredential(cred),
+		manager.WithLabels(req.Labels),
+		manager.WithEnv(req.Env),
+		manager.WithWorkingDir(req.WorkingDir),
+		manager.WithInheritEnv(req.InheritEnv),
+		manager.WithStdin(req.OpenStdin),
+		manager.WithTTY(req.Tty, rows, cols),
+		manager.WithTimeout(timeout),
+		manager.WithLogLimit(logLimit, logPolicy),
+	}
 	if getEnvWithDefault("JOBWORKER_SERVER_TEST", "") != "" {
 		jobOpts = append(jobOpts, manager.WithCgroup(nil), manager.WithCloneFlags(0))
 	}
 
 	jobInfo, err := s.jobManager.StartJob(context.Background(), req.Command, req.Arguments, jobOpts...)
-	if err != nil {
+	if jobInfo == nil {
 		return &pb.JobResponse{}, err
 	}
 
+	// A job that failed to start is kept, so its owner can see why and
+	// delete it
 	s.authHandler.registerJobID(jobInfo.JobID(), owner)
 
+	if err != nil {
+		log.Printf("StartJob: %v", err)
+	}
+
 	return jobResponseFromJobInfo(jobInfo), nil
 }
 
@@ -148,13 +308,19 @@ func (s *JobWorkerServer) QueryJob(ctx context.Context, req *pb.JobRequest) (*pb
 
 // StopJob:
 // - Validates peer certificate
+// - Validates the requested signal and grace period
 // - Stops a a job in the manager
-func (s *JobWorkerServer) StopJob(ctx context.Context, req *pb.JobRequest) (*pb.JobResponse, error) {
+func (s *JobWorkerServer) StopJob(ctx context.Context, req *pb.StopJobRequest) (*pb.JobResponse, error) {
 	if err := s.authHandler.checkOwnership(ctx, req.JobId); err != nil {
 		return &pb.JobResponse{}, err
 	}
 
-	jobInfo, err := s.jobManager.StopJob(req.JobId)
+	opts, err := stopOptions(req, s.maxStopGracePeriod)
+	if err != nil {
+		return &pb.JobResponse{}, err
+	}
+
+	jobInfo, err := s.jobManager.StopJob(req.JobId, opts...)
 	if err != nil {
 		return &pb.JobResponse{}, err
 	}
@@ -164,30 +330,377 @@ func (s *JobWorkerServer) StopJob(ctx context.Context, req *pb.JobRequest) (*pb.
 
 // StreamJob:
 // - Validates peer certificate
-// - Requests stream from the manager
+// - Requests stream from the manager, for the requested output streams and
+// starting at the requested position
 // - Reads from the channel provided by the manager and streams the received data
+// - The manager's stream is released as soon as the client goes away
 func (s *JobWorkerServer) StreamJob(req *pb.JobRequest, stream pb.JobWorker_StreamJobServer) error {
 	if err := s.authHandler.checkOwnership(stream.Context(), req.JobId); err != nil {
 		return err
 	}
 
-	outChannel, err := s.jobManager.StreamJob(req.JobId)
+	opts, err := streamOptions(req)
+	if err != nil {
+		return err
+	}
+
+	outChannel, err := s.jobManager.StreamJob(stream.Context(), req.JobId, opts...)
 	if err != nil {
 		return fmt.Errorf("failed calling manager stream for %s: %w", req.JobId, err)
 	}
 
-	for data := range outChannel {
+	return sendOutput(stream, outChannel, req.JobId)
+}
+
+// streamOptions converts the stream request to the manager's options,
+// invalid ones are an InvalidArgument
+func streamOptions(req *pb.JobRequest) ([]manager.StreamOption, error) {
+	streams, err := outputStreams(req.Streams)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Offset < 0 || req.TailLines < 0 {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"offset %d and tail_lines %d must not be negative", req.Offset, req.TailLines)
+	}
+
+	opts := []manager.StreamOption{
+		manager.WithStreams(streams...),
+		manager.WithOffset(req.Offset),
+		manager.WithTail(int(req.TailLines)),
+		manager.WithFollow(!req.NoFollow),
+	}
+
+	if req.Since != nil {
+		if err := req.Since.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid since: %v", err)
+		}
+		opts = append(opts, manager.WithSince(req.Since.AsTime()))
+	}
+
+	return opts, nil
+}
+
+// outputStreams converts the requested streams, an unknown one is an
+// InvalidArgument
+func outputStreams(pbStreams []pb.OutputStream) ([]manager.OutputStream, error) {
+	var streams []manager.OutputStream
+
+	for _, pbStream := range pbStreams {
+		found := false
+		for stream, s := range OutputStreamMap {
+			if s == pbStream {
+				streams = append(streams, stream)
+				found = true
+			}
+		}
+		if !found {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown output stream %v", pbStream)
+		}
+	}
+
+	return streams, nil
+}
+
+// outputSender is the server side of both StreamJob and AttachJob
+type outputSender interface {
+	Send(*pb.StreamJobResponse) error
+}
+
+// sendOutput:
+// - Sends the job's output until outChannel is closed, each message is
+// tagged with the stream it came from and the time it was captured at
+// - The output isn't read faster than it is sent, so a slow client slows
+// down its stream instead of losing output
+func sendOutput(stream outputSender, outChannel <-chan manager.OutputChunk, jobID string) error {
+	for chunk := range outChannel {
 		res := pb.StreamJobResponse{
-			Message: data,
+			Message: chunk.Data,
+			Stream:  OutputStreamMap[chunk.Stream],
+			Offset:  chunk.Offset,
+		}
+		if !chunk.Time.IsZero() {
+			res.Time = timestamppb.New(chunk.Time)
 		}
 		if err := stream.Send(&res); err != nil {
-			return fmt.Errorf("failed sending output %s: %w", req.JobId, err)
+			return fmt.Errorf("failed sending output %s: %w", jobID, err)
+		}
+	}
+
+	return nil
+}
+
+// AttachJob:
+// - Receives the first message which holds the job ID
+// - Validates peer certificate
+// - Attaches to the job's stdin, only a single client may be attached
+// - Forwards stdin messages to the job in the background
+// - Streams the job's output like StreamJob does
+func (s *JobWorkerServer) AttachJob(stream pb.JobWorker_AttachJobServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("failed receiving attach request: %w", err)
+	}
+
+	if err := s.authHandler.checkOwnership(stream.Context(), req.JobId); err != nil {
+		return err
+	}
+
+	stdin, err := s.jobManager.AttachStdin(req.JobId)
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "failed attaching to %s: %v", req.JobId, err)
+	}
+
+	outChannel, err := s.jobManager.StreamJob(stream.Context(), req.JobId)
+	if err != nil {
+		stdin.Detach()
+		return fmt.Errorf("failed calling manager stream for %s: %w", req.JobId, err)
+	}
+
+	go forwardStdin(stream, stdin, req.JobId, req)
+
+	return sendOutput(stream, outChannel, req.JobId)
+}
+
+// forwardStdin:
+// - Runs in a goroutine until the client stops sending or disconnects
+// - Writes the received stdin data to the job, closing it when requested
+// - Resizes the job's terminal when requested
+// - Detaches from stdin when done, so another client may attach
+// - Detaches as soon as the client is gone, which interrupts a write that
+// is blocked on a job that doesn't read its stdin
+func forwardStdin(stream pb.JobWorker_AttachJobServer, stdin *manager.StdinWriter, jobID string, req *pb.AttachJobRequest) {
+	defer stdin.Detach()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-stream.Context().Done():
+			stdin.Detach()
+		case <-done:
+		}
+	}()
+
+	for {
+		if len(req.Stdin) > 0 {
+			if _, err := stdin.Write(req.Stdin); err != nil {
+				log.Printf("Failed writing stdin for %s: %v", jobID, err)
+				return
+			}
+		}
+
+		if req.Resize != nil {
+			rows, cols, err := windowSize(req.Resize)
+			if err == nil {
+				err = stdin.Resize(rows, cols)
+			}
+			if err != nil {
+				log.Printf("Failed resizing terminal for %s: %v", jobID, err)
+			}
+		}
+
+		if req.CloseStdin {
+			if err := stdin.CloseStdin(); err != nil {
+				log.Printf("Failed closing stdin for %s: %v", jobID, err)
+			}
+			return
+		}
+
+		var err error
+		if req, err = stream.Recv(); err != nil {
+			if err != io.EOF {
+				log.Printf("Failed receiving stdin for %s: %v", jobID, err)
+			}
+			return
+		}
+	}
+}
+
+// WatchJob:
+// - Validates peer certificate
+// - Watches the job's status in the manager
+// - Sends the job's state for every status it goes through, until it stops
+// - Only the status of a response is that of its event, the other fields
+// are read when it is sent, e.g the running event of a job that already
+// stopped carries its exit code
+func (s *JobWorkerServer) WatchJob(req *pb.JobRequest, stream pb.JobWorker_WatchJobServer) error {
+	if err := s.authHandler.checkOwnership(stream.Context(), req.JobId); err != nil {
+		return err
+	}
+
+	jobInfo, err := s.jobManager.QueryJob(req.JobId)
+	if err != nil {
+		return err
+	}
+
+	statusChannel, err := s.jobManager.WatchJob(stream.Context(), req.JobId)
+	if err != nil {
+		return fmt.Errorf("failed calling manager watch for %s: %w", req.JobId, err)
+	}
+
+	for jobStatus := range statusChannel {
+		// The job may have moved on already, so the status is overridden
+		// with that of this event, while the other fields stay current
+		res := jobResponseFromJobInfo(jobInfo)
+		res.Status = StatusMap[jobStatus]
+		if err := stream.Send(res); err != nil {
+			return fmt.Errorf("failed sending status %s: %w", req.JobId, err)
 		}
 	}
 
 	return nil
 }
 
+// StreamJobMetrics:
+// - Validates peer certificate
+// - Validates the requested interval, a second by default
+// - Sends a sample of the job's resources every interval, until it stops
+func (s *JobWorkerServer) StreamJobMetrics(req *pb.JobMetricsRequest, stream pb.JobWorker_StreamJobMetricsServer) error {
+	if err := s.authHandler.checkOwnership(stream.Context(), req.JobId); err != nil {
+		return err
+	}
+
+	interval, err := metricsInterval(req.Interval)
+	if err != nil {
+		return err
+	}
+
+	metricsChannel, err := s.jobManager.StreamJobMetrics(stream.Context(), req.JobId, interval)
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "failed sampling job %s: %v", req.JobId, err)
+	}
+
+	for metrics := range metricsChannel {
+		if err := stream.Send(metricsResponse(metrics)); err != nil {
+			return fmt.Errorf("failed sending metrics of %s: %w", req.JobId, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteJob:
+// - Validates peer certificate
+// - Deletes a stopped job and its output in the manager
+func (s *JobWorkerServer) DeleteJob(ctx context.Context, req *pb.JobRequest) (*pb.JobResponse, error) {
+	if err := s.authHandler.checkOwnership(ctx, req.JobId); err != nil {
+		return &pb.JobResponse{}, err
+	}
+
+	jobInfo, err := s.jobManager.DeleteJob(req.JobId)
+	if err != nil {
+		return &pb.JobResponse{}, status.Errorf(codes.FailedPrecondition, "failed deleting job %s: %v", req.JobId, err)
+	}
+
+	return jobResponseFromJobInfo(jobInfo), nil
+}
+
+// PruneJobs:
+// - Validates peer certificate
+// - Deletes the calling client's stopped jobs that match the request filters
+func (s *JobWorkerServer) PruneJobs(ctx context.Context, req *pb.PruneJobsRequest) (*pb.PruneJobsResponse, error) {
+	owner, err := s.authHandler.listJobsAllowed(ctx)
+	if err != nil {
+		return &pb.PruneJobsResponse{}, err
+	}
+
+	filter := &manager.JobFilter{
+		Owner:   owner,
+		Command: req.Command,
+		Labels:  req.Labels,
+	}
+
+	var finishedBefore time.Time
+	if req.OlderThan != nil {
+		if err := req.OlderThan.CheckValid(); err != nil || req.OlderThan.AsDuration() < 0 {
+			return &pb.PruneJobsResponse{}, status.Errorf(codes.InvalidArgument, "invalid older_than %v", req.OlderThan)
+		}
+		finishedBefore = time.Now().Add(-req.OlderThan.AsDuration())
+	}
+
+	res := &pb.PruneJobsResponse{}
+	for _, jobInfo := range s.jobManager.PruneJobs(filter, finishedBefore) {
+		res.Jobs = append(res.Jobs, jobResponseFromJobInfo(jobInfo))
+	}
+
+	return res, nil
+}
+
+// ListJobs:
+// - Validates peer certificate
+// - Returns an InvalidArgument for unknown statuses and invalid times
+// - Lists the jobs owned by the calling client that match the request filters
+func (s *JobWorkerServer) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	owner, err := s.authHandler.listJobsAllowed(ctx)
+	if err != nil {
+		return &pb.ListJobsResponse{}, err
+	}
+
+	filter := &manager.JobFilter{
+		Owner:   owner,
+		Command: req.Command,
+		Labels:  req.Labels,
+	}
+
+	if filter.Statuses, err = jobStatuses(req.Statuses); err != nil {
+		return &pb.ListJobsResponse{}, err
+	}
+
+	if req.StartedAfter != nil {
+		if err := req.StartedAfter.CheckValid(); err != nil {
+			return &pb.ListJobsResponse{}, status.Errorf(codes.InvalidArgument, "invalid started_after: %v", err)
+		}
+		filter.StartedAfter = req.StartedAfter.AsTime()
+	}
+
+	if req.StartedBefore != nil {
+		if err := req.StartedBefore.CheckValid(); err != nil {
+			return &pb.ListJobsResponse{}, status.Errorf(codes.InvalidArgument, "invalid started_before: %v", err)
+		}
+		filter.StartedBefore = req.StartedBefore.AsTime()
+	}
+
+	jobs, nextPageToken, err := s.jobManager.ListJobs(filter, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return &pb.ListJobsResponse{}, status.Errorf(codes.InvalidArgument, "failed listing jobs: %v", err)
+	}
+
+	res := &pb.ListJobsResponse{
+		NextPageToken: nextPageToken,
+	}
+
+	for _, jobInfo := range jobs {
+		res.Jobs = append(res.Jobs, jobResponseFromJobInfo(jobInfo))
+	}
+
+	return res, nil
+}
+
+// jobStatuses converts the requested statuses, an unknown one is an
+// InvalidArgument since dropping it could leave a filter that matches
+// every job
+func jobStatuses(pbStatuses []pb.JobStatus) ([]manager.JobStatus, error) {
+	var statuses []manager.JobStatus
+
+	for _, pbStatus := range pbStatuses {
+		found := false
+		for jobStatus, s := range StatusMap {
+			if s == pbStatus {
+				statuses = append(statuses, jobStatus)
+				found = true
+			}
+		}
+		if !found {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown job status %v", pbStatus)
+		}
+	}
+
+	return statuses, nil
+}
+
 // This is for fetching certificates dir and server port
 func getEnvWithDefault(envVar, defultVal string) string {
 	if val, ok := os.LookupEnv(envVar); ok {
@@ -198,10 +711,67 @@ func getEnvWithDefault(envVar, defultVal string) string {
 }
 
 func jobResponseFromJobInfo(jobInfo *manager.JobInfo) *pb.JobResponse {
-	return &pb.JobResponse{
-		JobId:    jobInfo.JobID(),
-		Pid:      jobInfo.ProcessID(),
-		ExitCode: jobInfo.ExitCode(),
-		Status:   StatusMap[jobInfo.Status()],
+	resp := &pb.JobResponse{
+		JobId:             jobInfo.JobID(),
+		Pid:               jobInfo.ProcessID(),
+		ExitCode:          jobInfo.ExitCode(),
+		Status:            StatusMap[jobInfo.Status()],
+		Limits:            limitsResponse(jobInfo.Limits()),
+		Command:           jobInfo.Command(),
+		Labels:            jobInfo.Labels(),
+		Tty:               jobInfo.TTY(),
+		TimedOut:          jobInfo.TimedOut(),
+		TerminationReason: TerminationReasonMap[jobInfo.TerminationReason()],
+		Signal:            int32(jobInfo.Signal()),
+		ErrorMessage:      jobInfo.ErrorMessage(),
+		CreatedAt:         timestamppb.New(jobInfo.CreatedAt()),
+		Duration:          durationpb.New(jobInfo.Duration()),
+		Executable:        jobInfo.Executable(),
+		Arguments:         jobInfo.Args(),
+		Owner:             jobInfo.Owner(),
+		LogTruncated:      jobInfo.LogTruncated(),
+		LogCompression:    LogCompressionMap[jobInfo.LogCompression()],
+		LogSize:           jobInfo.LogSize(),
+		StoredLogSize:     jobInfo.StoredLogSize(),
+		CompressionRatio:  jobInfo.CompressionRatio(),
+		Usage:             usageResponse(jobInfo.Usage()),
+	}
+
+	if logLimit := jobInfo.LogLimit(); logLimit > 0 {
+		resp.LogLimit = &pb.LogLimit{MaxBytes: logLimit}
+		for pbPolicy, policy := range LogPolicyMap {
+			if policy == jobInfo.LogPolicy() {
+				resp.LogLimit.Policy = pbPolicy
+			}
+		}
+	}
+
+	if startedAt := jobInfo.StartedAt(); !startedAt.IsZero() {
+		resp.StartedAt = timestamppb.New(startedAt)
+	}
+
+	if finishedAt := jobInfo.FinishedAt(); !finishedAt.IsZero() {
+		resp.FinishedAt = timestamppb.New(finishedAt)
+	}
+
+	if deadline := jobInfo.Deadline(); !deadline.IsZero() {
+		resp.Deadline = timestamppb.New(deadline)
+		resp.Remaining = durationpb.New(jobInfo.Remaining())
 	}
+
+	return resp
+}
+
+// windowSize converts a terminal size request, a missing size keeps the
+// terminal's default.
+func windowSize(ws *pb.WindowSize) (uint16, uint16, error) {
+	if ws == nil {
+		return 0, 0, nil
+	}
+
+	if ws.Rows > math.MaxUint16 || ws.Cols > math.MaxUint16 {
+		return 0, 0, status.Errorf(codes.InvalidArgument, "invalid window size %dx%d", ws.Rows, ws.Cols)
+	}
+
+	return uint16(ws.Rows), uint16(ws.Cols), nil
 }
@@ -9,7 +9,9 @@ import (
 	pb "jobworker/pkg/api"
 	"jobworker/pkg/manager"
 	"jobworker/pkg/server"
+	"jobworker/pkg/server/servertest"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,7 +19,12 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func getClient(t *testing.T, clientName string) pb.JobWorkerClient {
@@ -65,6 +72,9 @@ func getServer(t *testing.T, port string) *server.JobWorkerServer {
 	os.Setenv("JOBWORKER_SERVER_TEST", "yes")
 	os.Setenv("JOBWORKER_SERVER_CERT_DIR", "../../certs")
 	os.Setenv("JOBWORKER_SERVER_PORT", port)
+	os.Setenv("JOBWORKER_SERVER_USER_MAP", servertest.WriteUserMap(t))
+	os.Setenv("JOBWORKER_SERVER_LOG_DIR", t.TempDir())
+	os.Setenv("JOBWORKER_SERVER_MAX_TIMEOUT", "1h")
 
 	srv, err := server.NewJobWorkerServer()
 	if err != nil {
@@ -118,6 +128,49 @@ func checkStatus(t *testing.T, cli pb.JobWorkerClient, jobID string, status mana
 	}
 }
 
+// TestServerRestart is not parallel, the second server relies on the
+// environment set up for the first one
+func TestServerRestart(t *testing.T) {
+	srv := getServer(t, "4573")
+
+	aliceClient := getClient(t, "alice")
+	bobClient := getClient(t, "bob")
+
+	res, err := aliceClient.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "echo",
+		Arguments: []string{"persisted"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	if err = checkStreamContains(aliceClient, res.JobId, "persisted"); err != nil {
+		t.Fatalf("stream check failed: %v", err)
+	}
+
+	srv.Close()
+
+	srv, err = server.NewJobWorkerServer()
+	if err != nil {
+		t.Fatalf("failed creating server: %v", err)
+	}
+	defer srv.Close()
+
+	go srv.Serve()
+	time.Sleep(time.Second)
+
+	checkStatus(t, aliceClient, res.JobId, manager.JobStopped)
+
+	if err = checkStreamContains(aliceClient, res.JobId, "persisted"); err != nil {
+		t.Fatalf("stream check after restart failed: %v", err)
+	}
+
+	_, err = bobClient.QueryJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected bob to be denied after restart, received %v", err)
+	}
+}
+
 func TestServerShortLivingJob(t *testing.T) {
 	t.Parallel()
 
@@ -148,6 +201,169 @@ func TestServerShortLivingJob(t *testing.T) {
 	}
 }
 
+func TestServerJobMetadata(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4576")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "ls",
+		Arguments: []string{"-l", "/dev/null"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	if err = checkStreamContains(cli, res.JobId, "/dev/null"); err != nil {
+		t.Fatalf("stream check failed: %v", err)
+	}
+	checkStatus(t, cli, res.JobId, manager.JobStopped)
+
+	res, err = cli.QueryJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if err != nil {
+		t.Fatalf("failed calling QueryJob: %v", err)
+	}
+
+	if res.Owner != "alice" || filepath.Base(res.Executable) != "ls" ||
+		strings.Join(res.Arguments, " ") != "-l /dev/null" {
+		t.Fatalf("unexpected job metadata %v", res)
+	}
+
+	createdAt, startedAt, finishedAt := res.CreatedAt.AsTime(), res.StartedAt.AsTime(), res.FinishedAt.AsTime()
+	if startedAt.Before(createdAt) || finishedAt.Before(startedAt) ||
+		res.Duration.AsDuration() != finishedAt.Sub(startedAt) {
+		t.Fatalf("unexpected job times %v", res)
+	}
+}
+
+func TestServerJobUsage(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4577")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "ls",
+		Arguments: []string{"-l", "/dev/null"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	if err = checkStreamContains(cli, res.JobId, "/dev/null"); err != nil {
+		t.Fatalf("stream check failed: %v", err)
+	}
+	checkStatus(t, cli, res.JobId, manager.JobStopped)
+
+	res, err = cli.QueryJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if err != nil {
+		t.Fatalf("failed calling QueryJob: %v", err)
+	}
+
+	if res.Usage == nil || res.Usage.Process == nil || res.Usage.Process.MaxRssBytes <= 0 {
+		t.Fatalf("expected the usage of the stopped job, received %v", res.Usage)
+	}
+}
+
+func TestServerStreamOptions(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4578")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	// Output is tagged with its stream, which may be requested on its own
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "bash",
+		Arguments: []string{"-c", "echo out; sleep 0.2; echo err >&2"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	messages, err := streamMessages(cli, &pb.JobRequest{
+		JobId:   res.JobId,
+		Streams: []pb.OutputStream{pb.OutputStream_outputStderr},
+	})
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Stream != pb.OutputStream_outputStderr ||
+		string(messages[0].Message) != "err\n" || messages[0].Offset != int64(len("out\n")) || messages[0].Time == nil {
+		t.Fatalf("expected stderr only, received %v", messages)
+	}
+
+	messages, err = streamMessages(cli, &pb.JobRequest{JobId: res.JobId, TailLines: 1, NoFollow: true})
+	if err != nil || len(messages) != 1 || string(messages[0].Message) != "err\n" {
+		t.Fatalf("expected the last line, received %v: %v", messages, err)
+	}
+
+	_, err = streamMessages(cli, &pb.JobRequest{JobId: res.JobId, TailLines: -1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected a negative tail to be rejected, received %v", err)
+	}
+	checkStatus(t, cli, res.JobId, manager.JobStopped)
+
+	_, err = streamMessages(cli, &pb.JobRequest{JobId: res.JobId, Streams: []pb.OutputStream{7}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an unknown stream to be rejected, received %v", err)
+	}
+}
+
+func TestServerJobFailedToStart(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4574")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{Command: "no-such-command"})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	if res.Status != pb.JobStatus_jobFailedToStart || res.TerminationReason != pb.TerminationReason_terminationFailedToStart ||
+		!strings.Contains(res.ErrorMessage, "no-such-command") {
+		t.Fatalf("expected the job to fail to start, received %v", res)
+	}
+
+	// The owner may still query and delete the job
+	res, err = cli.QueryJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if err != nil || res.ErrorMessage == "" {
+		t.Fatalf("expected the failed job to be queried, received %v: %v", res, err)
+	}
+
+	if _, err := cli.DeleteJob(context.Background(), &pb.JobRequest{JobId: res.JobId}); err != nil {
+		t.Fatalf("failed deleting the failed job: %v", err)
+	}
+}
+
+func streamMessages(cli pb.JobWorkerClient, req *pb.JobRequest) ([]*pb.StreamJobResponse, error) {
+	stream, err := cli.StreamJob(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*pb.StreamJobResponse
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			return messages, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, data)
+	}
+}
+
 func TestServerLongRunningJob(t *testing.T) {
 	t.Parallel()
 
@@ -170,7 +386,24 @@ func TestServerLongRunningJob(t *testing.T) {
 	log.Printf("Sleeping %v", dur)
 	time.Sleep(dur)
 
-	_, err = cli.StopJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	_, err = cli.StopJob(context.Background(), &pb.StopJobRequest{JobId: res.JobId, Signal: "BOGUS"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an unknown signal to be rejected, got %v", err)
+	}
+
+	_, err = cli.StopJob(context.Background(), &pb.StopJobRequest{
+		JobId:       res.JobId,
+		GracePeriod: durationpb.New(-time.Second),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected a negative grace period to be rejected, got %v", err)
+	}
+
+	_, err = cli.StopJob(context.Background(), &pb.StopJobRequest{
+		JobId:       res.JobId,
+		Signal:      "INT",
+		GracePeriod: durationpb.New(time.Second),
+	})
 	if err != nil {
 		t.Fatalf("Failed to stop job: %v", err)
 	}
@@ -178,3 +411,471 @@ func TestServerLongRunningJob(t *testing.T) {
 	checkStreamContains(cli, res.JobId, "hello")
 	checkStatus(t, cli, res.JobId, manager.JobStopped)
 }
+
+func TestServerStreamJobMetrics(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4579")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "sleep",
+		Arguments: []string{"60"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+	defer cli.StopJob(context.Background(), &pb.StopJobRequest{JobId: res.JobId})
+
+	checkStatus(t, cli, res.JobId, manager.JobRunning)
+
+	_, err = receiveMetrics(cli, &pb.JobMetricsRequest{JobId: res.JobId, Interval: durationpb.New(time.Millisecond)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected an interval below the minimum to be rejected, got %v", err)
+	}
+
+	// Jobs of the test server run without a cgroup, so there is nothing to
+	// sample.  Rendering samples received over gRPC is tested by the
+	// client's top command.
+	_, err = receiveMetrics(cli, &pb.JobMetricsRequest{JobId: res.JobId})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected sampling a job without a cgroup to fail, got %v", err)
+	}
+
+	bobClient := getClient(t, "bob")
+	_, err = receiveMetrics(bobClient, &pb.JobMetricsRequest{JobId: res.JobId})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected bob to be denied alice's metrics, got %v", err)
+	}
+}
+
+func receiveMetrics(cli pb.JobWorkerClient, req *pb.JobMetricsRequest) ([]*pb.JobMetrics, error) {
+	stream, err := cli.StreamJobMetrics(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []*pb.JobMetrics
+	for {
+		metrics, err := stream.Recv()
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, metrics)
+	}
+}
+
+func TestServerResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4568")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	limits := &pb.ResourceLimits{
+		Cpus:          0.5,
+		MemoryBytes:   64 << 20,
+		IoBytesPerSec: 10 << 20,
+		IoLimits:      []*pb.IOLimit{{Device: "/", WriteBytesPerSec: 1 << 20, ReadIops: 100}},
+	}
+
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command: "true",
+		Limits:  limits,
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	// The io limit of / keeps the job's io rate for reads, and its write
+	// iops are bounded by the maximum
+	expected := proto.Clone(limits).(*pb.ResourceLimits)
+	expected.IoLimits[0].ReadBytesPerSec = 10 << 20
+	expected.IoLimits[0].WriteIops = 100_000
+	if !proto.Equal(res.Limits, expected) {
+		t.Fatalf("expected limits %v, received %v", expected, res.Limits)
+	}
+
+	// Jobs that did not request limits get the defaults
+	res, err = cli.StartJob(context.Background(), &pb.StartJobRequest{Command: "true"})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	expected = &pb.ResourceLimits{Cpus: 1, MemoryBytes: 1 << 30, IoBytesPerSec: 100 << 20}
+	if !proto.Equal(res.Limits, expected) {
+		t.Fatalf("expected default limits %v, received %v", expected, res.Limits)
+	}
+
+	_, err = cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command: "true",
+		Limits:  &pb.ResourceLimits{MemoryBytes: 1 << 50},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for excessive memory, received %v", err)
+	}
+
+	for _, cpus := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err = cli.StartJob(context.Background(), &pb.StartJobRequest{
+			Command: "true",
+			Limits:  &pb.ResourceLimits{Cpus: cpus},
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for %v cpus, received %v", cpus, err)
+		}
+	}
+
+	invalidIOLimits := []*pb.IOLimit{
+		{Device: "/dev/null", ReadIops: 100},
+		{Device: "/", ReadBytesPerSec: 1 << 40},
+		{Device: "/", WriteIops: 1 << 40},
+	}
+	for _, ioLimit := range invalidIOLimits {
+		_, err = cli.StartJob(context.Background(), &pb.StartJobRequest{
+			Command: "true",
+			Limits:  &pb.ResourceLimits{IoLimits: []*pb.IOLimit{ioLimit}},
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for io limit %v, received %v", ioLimit, err)
+		}
+	}
+
+	logLimit := &pb.LogLimit{MaxBytes: 1 << 20, Policy: pb.LogPolicy_logRotate}
+	res, err = cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:  "true",
+		LogLimit: logLimit,
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	if !proto.Equal(res.LogLimit, logLimit) {
+		t.Fatalf("expected log limit %v, received %v", logLimit, res.LogLimit)
+	}
+
+	for _, logLimit := range []*pb.LogLimit{{MaxBytes: 1 << 40}, {MaxBytes: -1}, {Policy: 7}} {
+		_, err = cli.StartJob(context.Background(), &pb.StartJobRequest{
+			Command:  "true",
+			LogLimit: logLimit,
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for log limit %v, received %v", logLimit, err)
+		}
+	}
+}
+
+func TestServerJobTimeout(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4571")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	_, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command: "true",
+		Timeout: durationpb.New(2 * time.Hour),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for excessive timeout, received %v", err)
+	}
+
+	// Without a timeout the job gets the maximum
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{Command: "true"})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	if res.Deadline == nil {
+		t.Fatalf("expected the job to have a deadline")
+	}
+
+	res, err = cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "sleep",
+		Arguments: []string{"100"},
+		Timeout:   durationpb.New(time.Second),
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	if res.Remaining.AsDuration() <= 0 || res.Remaining.AsDuration() > time.Second {
+		t.Fatalf("unexpected remaining time %v", res.Remaining.AsDuration())
+	}
+
+	checkStreamContains(cli, res.JobId, "")
+	checkStatus(t, cli, res.JobId, manager.JobStopped)
+
+	res, err = cli.QueryJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if err != nil {
+		t.Fatalf("failed calling QueryJob: %v", err)
+	}
+
+	if !res.TimedOut || res.TerminationReason != pb.TerminationReason_terminationTimedOut {
+		t.Fatalf("expected the job to time out, received %v", res.TerminationReason)
+	}
+}
+
+func TestServerWatchJob(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4572")
+	defer srv.Close()
+
+	aliceClient := getClient(t, "alice")
+	bobClient := getClient(t, "bob")
+
+	res, err := aliceClient.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "bash",
+		Arguments: []string{"-c", "sleep 0.5; exit 3"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	// Running jobs can't be deleted
+	_, err = aliceClient.DeleteJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected deleting a running job to fail, received %v", err)
+	}
+
+	stream, err := aliceClient.WatchJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if err != nil {
+		t.Fatalf("failed calling WatchJob: %v", err)
+	}
+
+	var statuses []pb.JobStatus
+	var exitCode int32
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed receiving watch: %v", err)
+		}
+		statuses = append(statuses, resp.Status)
+		exitCode = resp.ExitCode
+	}
+
+	if fmt.Sprint(statuses) != "[jobRunning jobStopped]" || exitCode != 3 {
+		t.Fatalf("expected statuses [jobRunning jobStopped] and exit code 3, received %v/%d", statuses, exitCode)
+	}
+
+	// Errors of streaming calls are returned when receiving
+	stream, err = bobClient.WatchJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected bob to be denied watching alice's job, received %v", err)
+	}
+	_, err = bobClient.DeleteJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected bob to be denied deleting alice's job, received %v", err)
+	}
+
+	if _, err := aliceClient.DeleteJob(context.Background(), &pb.JobRequest{JobId: res.JobId}); err != nil {
+		t.Fatalf("failed calling DeleteJob: %v", err)
+	}
+
+	// The job is gone, along with its ownership
+	_, err = aliceClient.QueryJob(context.Background(), &pb.JobRequest{JobId: res.JobId})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected deleted job to be inaccessible, received %v", err)
+	}
+}
+
+func TestServerListJobs(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4569")
+	defer srv.Close()
+
+	aliceClient := getClient(t, "alice")
+	bobClient := getClient(t, "bob")
+
+	res, err := aliceClient.StartJob(context.Background(), &pb.StartJobRequest{
+		Command: "true",
+		Labels:  map[string]string{"team": "infra"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	list, err := aliceClient.ListJobs(context.Background(), &pb.ListJobsRequest{
+		Labels: map[string]string{"team": "infra"},
+	})
+	if err != nil {
+		t.Fatalf("failed calling ListJobs: %v", err)
+	}
+	if len(list.Jobs) != 1 || list.Jobs[0].JobId != res.JobId {
+		t.Fatalf("expected alice to list job %s, received %v", res.JobId, list.Jobs)
+	}
+
+	// bob should not see alice's jobs
+	list, err = bobClient.ListJobs(context.Background(), &pb.ListJobsRequest{})
+	if err != nil {
+		t.Fatalf("failed calling ListJobs: %v", err)
+	}
+	if len(list.Jobs) != 0 {
+		t.Fatalf("bob listed alice's jobs: %v", list.Jobs)
+	}
+
+	// Filters that can't be applied are rejected rather than ignored
+	invalidRequests := []*pb.ListJobsRequest{
+		{Statuses: []pb.JobStatus{42}},
+		{StartedAfter: &timestamppb.Timestamp{Seconds: -1 << 62}},
+		{StartedBefore: &timestamppb.Timestamp{Nanos: -1}},
+	}
+	for _, req := range invalidRequests {
+		_, err = aliceClient.ListJobs(context.Background(), req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for %v, received %v", req, err)
+		}
+	}
+}
+
+func TestServerAttachJob(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4570")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "cat",
+		OpenStdin: true,
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+
+	stream, err := cli.AttachJob(context.Background())
+	if err != nil {
+		t.Fatalf("failed calling AttachJob: %v", err)
+	}
+
+	if err := stream.Send(&pb.AttachJobRequest{JobId: res.JobId, Stdin: []byte("hello\n")}); err != nil {
+		t.Fatalf("failed sending stdin: %v", err)
+	}
+
+	// Wait for the echo, which also means we are attached
+	data, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed receiving output: %v", err)
+	}
+	if string(data.Message) != "hello\n" {
+		t.Fatalf("expected hello, received %q", data.Message)
+	}
+
+	// Only a single client may hold stdin
+	second, err := cli.AttachJob(context.Background())
+	if err != nil {
+		t.Fatalf("failed calling AttachJob: %v", err)
+	}
+	if err := second.Send(&pb.AttachJobRequest{JobId: res.JobId}); err != nil {
+		t.Fatalf("failed sending attach request: %v", err)
+	}
+	if _, err := second.Recv(); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected a second attach to fail, received %v", err)
+	}
+
+	// Closing stdin makes cat exit, which ends the stream
+	if err := stream.Send(&pb.AttachJobRequest{Stdin: []byte("bye\n"), CloseStdin: true}); err != nil {
+		t.Fatalf("failed closing stdin: %v", err)
+	}
+
+	output := ""
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed receiving output: %v", err)
+		}
+		output += string(data.Message)
+	}
+
+	if output != "bye\n" {
+		t.Fatalf("expected bye, received %q", output)
+	}
+
+	checkStatus(t, cli, res.JobId, manager.JobStopped)
+}
+
+func TestServerAttachJobDisconnect(t *testing.T) {
+	t.Parallel()
+
+	srv := getServer(t, "4575")
+	defer srv.Close()
+
+	cli := getClient(t, "alice")
+
+	res, err := cli.StartJob(context.Background(), &pb.StartJobRequest{
+		Command:   "bash",
+		Arguments: []string{"-c", "read line; echo ready; sleep 60"},
+		OpenStdin: true,
+	})
+	if err != nil {
+		t.Fatalf("failed calling StartJob: %v", err)
+	}
+	defer cli.StopJob(context.Background(), &pb.StopJobRequest{JobId: res.JobId})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := cli.AttachJob(ctx)
+	if err != nil {
+		t.Fatalf("failed calling AttachJob: %v", err)
+	}
+
+	// The job stops reading after the first line, so the rest of stdin
+	// doesn't fit in the pipe and the write blocks
+	stdin := append([]byte("hello\n"), make([]byte, 1<<20)...)
+	if err := stream.Send(&pb.AttachJobRequest{JobId: res.JobId, Stdin: stdin}); err != nil {
+		t.Fatalf("failed sending stdin: %v", err)
+	}
+
+	data, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("failed receiving output: %v", err)
+	}
+	if string(data.Message) != "ready\n" {
+		t.Fatalf("expected ready, received %q", data.Message)
+	}
+
+	// Disconnecting releases stdin to another client
+	cancel()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		second, err := cli.AttachJob(context.Background())
+		if err != nil {
+			t.Fatalf("failed calling AttachJob: %v", err)
+		}
+		if err := second.Send(&pb.AttachJobRequest{JobId: res.JobId}); err != nil {
+			t.Fatalf("failed sending attach request: %v", err)
+		}
+		if err := second.CloseSend(); err != nil {
+			t.Fatalf("failed closing attach request: %v", err)
+		}
+
+		_, err = second.Recv()
+		if status.Code(err) != codes.FailedPrecondition {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected stdin to be released once the client disconnected, received %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,27 @@
+// Package servertest holds helpers for tests that run a JobWorkerServer.
+package servertest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// WriteUserMap maps the test clients to the user running the tests, and
+// returns the path of the user map
+func WriteUserMap(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "users.conf")
+	data := ""
+	for _, name := range []string{"alice", "bob"} {
+		data += fmt.Sprintf("%s:%d:%d\n", name, os.Getuid(), os.Getgid())
+	}
+
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed writing user map: %v", err)
+	}
+
+	return path
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"fmt"
+	pb "jobworker/pkg/api"
+	"jobworker/pkg/manager"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultMaxStopGracePeriod = 5 * time.Minute
+
+// newMaxStopGracePeriod reads the longest grace period a client may request
+// when stopping a job from the environment.
+func newMaxStopGracePeriod() (time.Duration, error) {
+	maxGrace, err := time.ParseDuration(
+		getEnvWithDefault("JOBWORKER_SERVER_MAX_STOP_GRACE_PERIOD", defaultMaxStopGracePeriod.String()))
+	if err != nil {
+		return 0, fmt.Errorf("invalid JOBWORKER_SERVER_MAX_STOP_GRACE_PERIOD: %w", err)
+	}
+
+	return maxGrace, nil
+}
+
+// stopOptions:
+// - Converts the requested signal and grace period to manager options.
+// - Returns an InvalidArgument for unknown signals and grace periods which
+// are negative or longer than maxGrace.
+func stopOptions(req *pb.StopJobRequest, maxGrace time.Duration) ([]manager.StopOption, error) {
+	var opts []manager.StopOption
+
+	if req.Signal != "" {
+		sig, err := parseSignal(req.Signal)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		opts = append(opts, manager.WithStopSignal(sig))
+	}
+
+	if req.GracePeriod != nil {
+		if err := req.GracePeriod.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid grace period: %v", err)
+		}
+
+		grace := req.GracePeriod.AsDuration()
+		if grace < 0 || grace > maxGrace {
+			return nil, status.Errorf(codes.InvalidArgument, "grace period must be between 0 and %v", maxGrace)
+		}
+		opts = append(opts, manager.WithGracePeriod(grace))
+	}
+
+	return opts, nil
+}
+
+// parseSignal accepts a signal's name with or without the SIG prefix, or
+// its number.
+func parseSignal(name string) (unix.Signal, error) {
+	if num, err := strconv.Atoi(name); err == nil {
+		if unix.SignalName(unix.Signal(num)) == "" {
+			return 0, fmt.Errorf("unknown signal %s", name)
+		}
+		return unix.Signal(num), nil
+	}
+
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return 0, fmt.Errorf("unknown signal %s", name)
+	}
+
+	return sig, nil
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	pb "jobworker/pkg/api"
+	"jobworker/pkg/manager"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// usageResponse converts the job's resource usage, nil if it isn't known
+func usageResponse(usage *manager.ResourceUsage) *pb.ResourceUsage {
+	if usage == nil {
+		return nil
+	}
+
+	resp := &pb.ResourceUsage{
+		ReadAt:             timestamppb.New(usage.ReadAt),
+		CpuUsage:           durationpb.New(usage.CPUUsage),
+		CpuUser:            durationpb.New(usage.CPUUser),
+		CpuSystem:          durationpb.New(usage.CPUSystem),
+		CpuThrottled:       durationpb.New(usage.CPUThrottled),
+		ThrottledPeriods:   usage.ThrottledPeriods,
+		MemoryCurrentBytes: usage.MemoryCurrent,
+		MemoryPeakBytes:    usage.MemoryPeak,
+		MemoryEvents: &pb.MemoryEvents{
+			Low:     usage.MemoryEvents.Low,
+			High:    usage.MemoryEvents.High,
+			Max:     usage.MemoryEvents.Max,
+			Oom:     usage.MemoryEvents.OOM,
+			OomKill: usage.MemoryEvents.OOMKill,
+		},
+		PidsCurrent: usage.PidsCurrent,
+	}
+
+	for _, device := range usage.IO {
+		resp.Io = append(resp.Io, &pb.DeviceIO{
+			Major:      device.Major,
+			Minor:      device.Minor,
+			ReadBytes:  device.ReadBytes,
+			WriteBytes: device.WriteBytes,
+			ReadIos:    device.ReadIOs,
+			WriteIos:   device.WriteIOs,
+		})
+	}
+
+	if process := usage.Process; process != nil {
+		resp.Process = &pb.ProcessUsage{
+			UserTime:            durationpb.New(process.UserTime),
+			SystemTime:          durationpb.New(process.SystemTime),
+			MaxRssBytes:         process.MaxRSS,
+			MinorFaults:         process.MinorFaults,
+			MajorFaults:         process.MajorFaults,
+			InBlocks:            process.InBlocks,
+			OutBlocks:           process.OutBlocks,
+			VoluntarySwitches:   process.VoluntarySwitches,
+			InvoluntarySwitches: process.InvoluntarySwitches,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"jobworker/pkg/manager"
+	"os"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userMap maps a client's certificate common name to the unix identity its
+// jobs are executed with.  It is loaded from a file with lines such as:
+//
+//	# name:uid:gid[:group,group...]
+//	alice:1001:1001:100,1002
+type userMap struct {
+	users map[string]*manager.Credential
+}
+
+// loadUserMap:
+// - Reads the mapping file, skipping blank lines and comments.
+// - Fails on malformed lines, so a typo won't silently deny (or grant) access.
+func loadUserMap(path string) (*userMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening user map %s: %w", path, err)
+	}
+	defer file.Close()
+
+	users := &userMap{
+		users: make(map[string]*manager.Credential),
+	}
+
+	lineNum := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, cred, err := parseUserMapLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNum, err)
+		}
+
+		users.users[name] = cred
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading user map %s: %w", path, err)
+	}
+
+	return users, nil
+}
+
+func parseUserMapLine(line string) (string, *manager.Credential, error) {
+	fields := strings.Split(line, ":")
+	if len(fields) < 3 || len(fields) > 4 || fields[0] == "" {
+		return "", nil, fmt.Errorf("expected name:uid:gid[:groups], got %q", line)
+	}
+
+	uid, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid uid %q: %w", fields[1], err)
+	}
+
+	gid, err := strconv.ParseUint(fields[2], 10, 32)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid gid %q: %w", fields[2], err)
+	}
+
+	cred := &manager.Credential{
+		UID: uint32(uid),
+		GID: uint32(gid),
+	}
+
+	if len(fields) == 4 && fields[3] != "" {
+		for _, group := range strings.Split(fields[3], ",") {
+			g, err := strconv.ParseUint(group, 10, 32)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid group %q: %w", group, err)
+			}
+			cred.Groups = append(cred.Groups, uint32(g))
+		}
+	}
+
+	return fields[0], cred, nil
+}
+
+// credential returns the identity a client's jobs run as, a client without
+// a mapping is not allowed to start jobs.
+func (m *userMap) credential(clientName string) (*manager.Credential, error) {
+	cred, ok := m.users[clientName]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "no unix user is mapped for %s", clientName)
+	}
+
+	return cred, nil
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUserMap(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "users.conf")
+	data := "# comment\n\nalice:1001:1002:10,20\nbob:1003:1004\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("failed writing user map: %v", err)
+	}
+
+	users, err := loadUserMap(path)
+	if err != nil {
+		t.Fatalf("failed loading user map: %v", err)
+	}
+
+	cred, err := users.credential("alice")
+	if err != nil {
+		t.Fatalf("alice should be mapped: %v", err)
+	}
+	if cred.UID != 1001 || cred.GID != 1002 || !reflect.DeepEqual(cred.Groups, []uint32{10, 20}) {
+		t.Fatalf("unexpected credential for alice: %+v", cred)
+	}
+
+	cred, err = users.credential("bob")
+	if err != nil {
+		t.Fatalf("bob should be mapped: %v", err)
+	}
+	if cred.UID != 1003 || cred.GID != 1004 || len(cred.Groups) != 0 {
+		t.Fatalf("unexpected credential for bob: %+v", cred)
+	}
+
+	if _, err := users.credential("eve"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for an unmapped user, received %v", err)
+	}
+
+	for _, line := range []string{"alice:1001", "alice:x:1001", "alice:1:2:a", ":1:2"} {
+		if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+			t.Fatalf("failed writing user map: %v", err)
+		}
+		if _, err := loadUserMap(path); err == nil {
+			t.Fatalf("expected malformed line %q to fail", line)
+		}
+	}
+}
@@ -13,9 +13,9 @@ sleep 1
 
 echo "--- Running short task ---"
 job_id=$(./jobclient start -- ls /dev/null | jq -r '.jobId')
-sleep 0.5
 
-stat=$(./jobclient status ${job_id} | jq -r '.status')
+# watch returns once the job stops, the last line is its final state
+stat=$(./jobclient watch ${job_id} | tail -1 | jq -r '.status')
 if [ ${stat} != "jobStopped" ]; then 
 	echo "Expected job status to be jobStopped"
 	exit 1
@@ -41,9 +41,8 @@ streampid=$!
 sleep 3
 
 ./jobclient stop ${job_id}
-sleep 0.5
 
-stat=$(./jobclient status ${job_id} | jq -r '.status')
+stat=$(./jobclient watch ${job_id} | tail -1 | jq -r '.status')
 if [ ${stat} != "jobStopped" ]; then 
 	echo "Expected job status to be jobStopped"
 	exit 1